@@ -0,0 +1,73 @@
+package ginlogger
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// abuseTracker counts 4xx responses per client IP in a sliding window. The
+// number of tracked IPs is bounded, the least recently active is evicted.
+type abuseTracker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	capacity  int
+	entries   map[string]*list.Element
+	order     *list.List
+}
+
+type abuseEntry struct {
+	ip string
+	// hits holds the times of recent 4xx responses, oldest first, at most threshold
+	hits []time.Time
+}
+
+func newAbuseTracker(threshold int, window time.Duration, capacity int) *abuseTracker {
+	return &abuseTracker{
+		threshold: threshold,
+		window:    window,
+		capacity:  capacity,
+		entries:   make(map[string]*list.Element),
+		order:     list.New(),
+	}
+}
+
+// Record adds a 4xx response from ip at now and reports whether the IP reached
+// threshold responses within the window. The count restarts after reporting,
+// so a persistent offender is reported once per threshold responses.
+func (t *abuseTracker) Record(ip string, now time.Time) (int, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var entry *abuseEntry
+	if element, ok := t.entries[ip]; ok {
+		t.order.MoveToFront(element)
+		entry = element.Value.(*abuseEntry)
+	} else {
+		entry = &abuseEntry{ip: ip}
+		t.entries[ip] = t.order.PushFront(entry)
+
+		if t.order.Len() > t.capacity {
+			oldest := t.order.Back()
+			t.order.Remove(oldest)
+			delete(t.entries, oldest.Value.(*abuseEntry).ip)
+		}
+	}
+
+	// Drop hits that left the window
+	cutoff := now.Add(-t.window)
+	i := 0
+	for i < len(entry.hits) && !entry.hits[i].After(cutoff) {
+		i++
+	}
+	entry.hits = append(entry.hits[i:], now)
+
+	if len(entry.hits) < t.threshold {
+		return len(entry.hits), false
+	}
+
+	count := len(entry.hits)
+	entry.hits = entry.hits[:0]
+	return count, true
+}
@@ -0,0 +1,76 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAbuseTrackerWindow(t *testing.T) {
+	tracker := newAbuseTracker(3, time.Minute, 10)
+	now := time.Now()
+
+	tracker.Record("203.0.113.7", now)
+	tracker.Record("203.0.113.7", now.Add(10*time.Second))
+	// The first hit left the window
+	if count, exceeded := tracker.Record("203.0.113.7", now.Add(61*time.Second)); exceeded || count != 2 {
+		t.Fatalf("Record() = %d, %v, want 2, false", count, exceeded)
+	}
+	if count, exceeded := tracker.Record("203.0.113.7", now.Add(62*time.Second)); !exceeded || count != 3 {
+		t.Fatalf("Record() = %d, %v, want 3, true", count, exceeded)
+	}
+	// The count restarts after reporting
+	if _, exceeded := tracker.Record("203.0.113.7", now.Add(63*time.Second)); exceeded {
+		t.Error("reported again right after reporting")
+	}
+}
+
+func TestAbuseTrackerEviction(t *testing.T) {
+	tracker := newAbuseTracker(2, time.Minute, 2)
+	now := time.Now()
+
+	tracker.Record("198.51.100.1", now)
+	tracker.Record("198.51.100.2", now)
+	tracker.Record("198.51.100.3", now)
+	if tracker.order.Len() != 2 {
+		t.Fatalf("tracking %d IPs, want at most 2", tracker.order.Len())
+	}
+	// The least recently active IP was evicted and starts over
+	if _, exceeded := tracker.Record("198.51.100.1", now); exceeded {
+		t.Error("evicted IP kept its hits")
+	}
+	if _, exceeded := tracker.Record("198.51.100.3", now); !exceeded {
+		t.Error("tracked IP lost its hits")
+	}
+}
+
+func TestSecurityLoggerAbuse(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(SecurityLoggerWithConfig(SecurityLoggerConfig{Logger: logger, AbuseThreshold: 3}))
+	r.POST("/login", func(c *gin.Context) { c.Status(http.StatusUnauthorized) })
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	send := func(method, path, ip string) {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = ip + ":1234"
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	for range 3 {
+		send(http.MethodGet, "/fail", "203.0.113.7")
+		send(http.MethodPost, "/login", "198.51.100.1")
+	}
+	send(http.MethodPost, "/login", "203.0.113.7")
+	send(http.MethodPost, "/login", "203.0.113.7")
+
+	if n := countEntries(t, buf, "Possible abuse detected"); n != 1 {
+		t.Fatalf("logged %d warnings, want one for the IP with repeated 4xx responses", n)
+	}
+	warning := findEntry(t, buf.entries(t), "Possible abuse detected")
+	if warning["ip"] != "198.51.100.1" || warning["client_error_count"] != float64(3) || warning["status"] != float64(401) || warning["window"] != float64(60) {
+		t.Errorf("warning = %v, want 3 401s from 198.51.100.1 within 1m", warning)
+	}
+}
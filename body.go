@@ -0,0 +1,312 @@
+package ginlogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AbsoluteMaxBodySize caps every body capture (request and response) of the
+// middleware in this package, whatever MaxBodySize is configured, so that a
+// single configuration mistake cannot exhaust memory. Zero or negative
+// disables the ceiling. Set it before creating the middleware.
+var AbsoluteMaxBodySize int64 = 10 * 1024 * 1024
+
+// bodyLimit returns the configured limit, capped at AbsoluteMaxBodySize
+func bodyLimit(configured int64) int64 {
+	if AbsoluteMaxBodySize > 0 && configured > AbsoluteMaxBodySize {
+		return AbsoluteMaxBodySize
+	}
+	return configured
+}
+
+// captureRequestBody reads up to limit bytes of the request body and restores
+// the full body for further processing. The read respects request context
+// cancellation so that a client disconnecting mid-upload aborts the capture
+// instead of blocking the middleware: a pending read is stopped through a read
+// deadline on the connection, or by closing the body when the writer cannot
+// set one. When the read fails, e.g. an upload ending before its
+// Content-Length, the bytes read so far are returned with the error.
+func captureRequestBody(c *gin.Context, limit int64) ([]byte, error) {
+	body := c.Request.Body
+	controller := http.NewResponseController(c.Writer)
+	bodyBytes, err := readAllContext(c.Request.Context(), io.LimitReader(body, bodyLimit(limit)), func() {
+		if controller.SetReadDeadline(time.Now()) != nil {
+			body.Close()
+		}
+	})
+	if err != nil {
+		// The body is partially consumed, hand the error to the handler
+		c.Request.Body = io.NopCloser(&errorReader{err: err})
+		return bodyBytes, err
+	}
+
+	// Replay the captured bytes, followed by anything beyond the limit
+	c.Request.Body = &readCloser{
+		Reader: io.MultiReader(bytes.NewReader(bodyBytes), body),
+		Closer: body,
+	}
+	return bodyBytes, nil
+}
+
+// shouldCaptureBody reports whether a body of the given declared length can be
+// captured. Unknown lengths (-1, e.g. chunked uploads) are only captured when
+// allowed, and always bounded by the limit.
+func shouldCaptureBody(r *http.Request, maxBodySize int64, captureUnknownLength bool) bool {
+	if r.Body == nil || r.Body == http.NoBody {
+		return false
+	}
+
+	if r.ContentLength < 0 {
+		return captureUnknownLength
+	}
+	return r.ContentLength <= maxBodySize
+}
+
+// readCloser combines a reader with the closer of the original body
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// readAllContext reads r until EOF or until ctx is done. On an error or
+// cancellation it returns the bytes read so far; net/http cancels the request
+// context when the connection fails, so both usually happen together. The read
+// runs on the calling goroutine: once ctx is done, interrupt unblocks it, and
+// nothing reads r after readAllContext returns.
+func readAllContext(ctx context.Context, r io.Reader, interrupt func()) ([]byte, error) {
+	interrupted := make(chan struct{})
+	stop := context.AfterFunc(ctx, func() {
+		defer close(interrupted)
+		interrupt()
+	})
+
+	data, err := io.ReadAll(r)
+	if !stop() {
+		<-interrupted
+	}
+	if err != nil && ctx.Err() != nil {
+		return data, ctx.Err()
+	}
+	return data, err
+}
+
+// bodyCaptureAbortReason describes why a body capture was aborted, or "" if
+// the error is not caused by the request context
+func bodyCaptureAbortReason(err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "context_cancelled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	default:
+		return ""
+	}
+}
+
+// errorReader always fails with err
+type errorReader struct {
+	err error
+}
+
+func (r *errorReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// nestedJSON logs a raw JSON document as a nested object. It deliberately has
+// no String method: zap.Any would encode such a value as an escaped string
+// (json.RawMessage gains one in newer Go releases), so it is logged with
+// zap.Reflect, which encodes it through MarshalJSON.
+type nestedJSON []byte
+
+func (j nestedJSON) MarshalJSON() ([]byte, error) {
+	return j, nil
+}
+
+// structuredJSONBody returns body as raw JSON when it is a valid JSON document
+// with a JSON content type, so that it can be logged as a nested object rather
+// than an escaped string. Arrays longer than maxArrayElements (when > 0) are
+// truncated and the second result reports whether any truncation happened.
+func structuredJSONBody(body, contentType string, maxArrayElements int) (json.RawMessage, bool, bool) {
+	if !isJSONContentType(contentType) || !json.Valid([]byte(body)) {
+		return nil, false, false
+	}
+
+	if maxArrayElements <= 0 {
+		return json.RawMessage(body), false, true
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false, false
+	}
+
+	value, truncated := truncateJSONArrays(value, maxArrayElements)
+	if !truncated {
+		return json.RawMessage(body), false, true
+	}
+
+	truncatedBody, err := json.Marshal(value)
+	if err != nil {
+		return nil, false, false
+	}
+	return truncatedBody, true, true
+}
+
+// truncateJSONArrays replaces the tail of arrays longer than limit with a
+// "...(N more)" marker
+func truncateJSONArrays(value any, limit int) (any, bool) {
+	truncated := false
+
+	switch v := value.(type) {
+	case []any:
+		if len(v) > limit {
+			more := len(v) - limit
+			v = append(v[:limit:limit], fmt.Sprintf("...(%d more)", more))
+			truncated = true
+		}
+		for i := range v {
+			var nested bool
+			v[i], nested = truncateJSONArrays(v[i], limit)
+			truncated = truncated || nested
+		}
+		return v, truncated
+	case map[string]any:
+		for key, item := range v {
+			var nested bool
+			v[key], nested = truncateJSONArrays(item, limit)
+			truncated = truncated || nested
+		}
+		return v, truncated
+	default:
+		return value, false
+	}
+}
+
+// isJSONContentType reports whether contentType is application/json or a +json type
+func isJSONContentType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// errDecompressionLimitExceeded reports a body that decompresses to more than
+// the configured limit, a potential zip bomb
+var errDecompressionLimitExceeded = errors.New("decompressed body exceeds limit")
+
+// decodeRequestBody decompresses a gzip or deflate encoded body, reading at
+// most limit decompressed bytes. Bodies without a supported encoding are
+// returned unchanged. Beyond limit, the first limit bytes are returned with
+// errDecompressionLimitExceeded.
+func decodeRequestBody(body []byte, encoding string, limit int64) ([]byte, error) {
+	var reader io.ReadCloser
+	var err error
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip", "x-gzip":
+		reader, err = gzip.NewReader(bytes.NewReader(body))
+	case "deflate":
+		reader, err = zlib.NewReader(bytes.NewReader(body))
+	default:
+		return body, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(io.LimitReader(reader, limit+1))
+	if err == nil && int64(len(decoded)) > limit {
+		return decoded[:limit], errDecompressionLimitExceeded
+	}
+	return decoded, err
+}
+
+// gzipDeclaredSize returns the uncompressed size stored in the trailer of a
+// complete gzip body (modulo 2^32, as written by the sender)
+func gzipDeclaredSize(body []byte) (int64, bool) {
+	// 10 byte header, at least 2 bytes of data and the 8 byte trailer
+	if len(body) < 20 || body[0] != 0x1f || body[1] != 0x8b {
+		return 0, false
+	}
+	return int64(binary.LittleEndian.Uint32(body[len(body)-4:])), true
+}
+
+// timingReader accumulates the time spent in reads of the request body. The
+// total is atomic because a read may still be running in another goroutine
+// (e.g. a handler reading the body in its own goroutine) when the
+// middleware logs it.
+type timingReader struct {
+	io.ReadCloser
+	elapsed atomic.Int64
+}
+
+func (r *timingReader) Read(p []byte) (int, error) {
+	start := time.Now()
+	n, err := r.ReadCloser.Read(p)
+	r.elapsed.Add(int64(time.Since(start)))
+	return n, err
+}
+
+// duration returns the time spent in reads that completed so far
+func (r *timingReader) duration() time.Duration {
+	return time.Duration(r.elapsed.Load())
+}
+
+// checksumReader hashes the request body as the handler reads it, so the body
+// is never read twice
+type checksumReader struct {
+	io.Reader
+	io.Closer
+	hash hash.Hash
+	eof  bool
+}
+
+// newChecksumReader wraps body so that everything read from it is hashed
+func newChecksumReader(body io.ReadCloser) *checksumReader {
+	h := sha256.New()
+	return &checksumReader{
+		Reader: io.TeeReader(body, h),
+		Closer: body,
+		hash:   h,
+	}
+}
+
+func (r *checksumReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+// sum returns the hex SHA-256 of the body. Up to limit bytes the handler did
+// not read are consumed first; larger remainders yield no checksum.
+func (r *checksumReader) sum(limit int64) (string, bool) {
+	if !r.eof {
+		io.Copy(io.Discard, io.LimitReader(r, limit))
+		if !r.eof {
+			// The remainder may end exactly at the limit
+			var probe [1]byte
+			if n, _ := r.Read(probe[:]); n > 0 || !r.eof {
+				return "", false
+			}
+		}
+	}
+	return hex.EncodeToString(r.hash.Sum(nil)), true
+}
@@ -0,0 +1,641 @@
+package ginlogger
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBodyCaptureAbortedByContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		ctx    func() (context.Context, context.CancelFunc)
+		reason string
+	}{
+		{"deadline", func() (context.Context, context.CancelFunc) {
+			return context.WithTimeout(context.Background(), 20*time.Millisecond)
+		}, "deadline_exceeded"},
+		{"cancelled", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(20*time.Millisecond, cancel)
+			return ctx, cancel
+		}, "context_cancelled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestBody: true})
+
+			// A client that never sends its body
+			body, writer := io.Pipe()
+			defer writer.Close()
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			req := httptest.NewRequest(http.MethodPost, "/", body).WithContext(ctx)
+			req.ContentLength = 10
+
+			var readErr error
+			start := time.Now()
+			serve(req, "/", func(c *gin.Context) {
+				_, readErr = io.ReadAll(c.Request.Body)
+				c.Status(http.StatusBadRequest)
+			}, middleware)
+
+			if elapsed := time.Since(start); elapsed > time.Second {
+				t.Fatalf("capture blocked for %v", elapsed)
+			}
+			if readErr == nil {
+				t.Fatal("handler read the body without error")
+			}
+			if entry := findEntry(t, buf.entries(t), "Client error"); entry["body_capture_aborted"] != tt.reason {
+				t.Fatalf("body_capture_aborted = %v, want %s", entry["body_capture_aborted"], tt.reason)
+			}
+		})
+	}
+}
+
+func TestCaptureRequestBodyRestoresBody(t *testing.T) {
+	logger, _ := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestBody: true, MaxBodySize: 4})
+
+	var received string
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123"))
+	serve(req, "/", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil && !errors.Is(err, io.EOF) {
+			t.Errorf("reading body: %v", err)
+		}
+		received = string(body)
+	}, middleware)
+
+	if received != "0123" {
+		t.Fatalf("handler received %q, want the full body", received)
+	}
+}
+
+func TestStructuredJSONBodyTruncatesNestedArrays(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      string
+		truncated bool
+	}{
+		{"within limit", `{"ids":[1,2]}`, `{"ids":[1,2]}`, false},
+		{"top level array", `[1,2,3]`, `[1,2,"...(1 more)"]`, true},
+		{"nested", `{"orders":[{"items":[1,2,3,4,5]}]}`, `{"orders":[{"items":[1,2,"...(3 more)"]}]}`, true},
+		{"large numbers", `{"ids":[12345678901234567890,2,3]}`, `{"ids":[12345678901234567890,2,"...(1 more)"]}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, truncated, structured := structuredJSONBody(tt.body, "application/json", 2)
+			if !structured || truncated != tt.truncated || string(body) != tt.want {
+				t.Fatalf("structuredJSONBody() = %s, %v, %v, want %s, %v, true", body, truncated, structured, tt.want, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestBodySampleRate(t *testing.T) {
+	for _, tt := range []struct {
+		rate   float64
+		status int
+		want   bool
+	}{
+		{0, http.StatusOK, true},
+		{1, http.StatusOK, true},
+		{1e-12, http.StatusOK, false},
+		{1e-12, http.StatusInternalServerError, false},
+	} {
+		logger, buf := newTestLogger()
+		middleware := StructuredLogger(StructuredLoggerConfig{
+			Logger:         logger,
+			LogRequestBody: true,
+			BodySampleRate: tt.rate,
+		})
+		body := strings.NewReader("payload")
+		serve(httptest.NewRequest(http.MethodPost, "/", body), "/", func(c *gin.Context) { c.Status(tt.status) }, middleware)
+
+		entries := buf.entries(t)
+		if len(entries) != 1 {
+			t.Fatalf("entries = %v, want one", entries)
+		}
+		_, logged := entries[0]["request_body"]
+		if logged != tt.want {
+			t.Errorf("BodySampleRate %v, status %d: body logged = %v, want %v", tt.rate, tt.status, logged, tt.want)
+		}
+		// Unsampled bodies are left for the handler, never buffered
+		if read := body.Len() == 0; read != tt.want {
+			t.Errorf("BodySampleRate %v, status %d: body read = %v, want %v", tt.rate, tt.status, read, tt.want)
+		}
+	}
+}
+
+func TestRequestBodyLoggerLevel(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	middleware := RequestBodyLogger(RequestBodyLoggerConfig{LogLevel: LevelWarn})
+
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gopher"}`)), "/", ok, middleware)
+
+	entry := findEntry(t, entries(), "Request body")
+	if entry["level"] != "warn" || entry["body"] != `{"name":"gopher"}` {
+		t.Fatalf("entry = %v, want the body at warn", entry)
+	}
+}
+
+func TestRequestBodyLoggerOnlyOnError(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	r := gin.New()
+	r.Use(RequestBodyLogger(RequestBodyLoggerConfig{LogLevel: LevelInfo, OnlyOnError: true}))
+	r.POST("/ok", ok)
+	r.POST("/invalid", func(c *gin.Context) { c.Status(http.StatusUnprocessableEntity) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/ok", strings.NewReader("fine")))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/invalid", strings.NewReader("broken")))
+
+	logged := entries()
+	if len(logged) != 1 || logged[0]["body"] != "broken" || logged[0]["status"] != float64(422) {
+		t.Fatalf("entries = %v, want only the failed request's body with its status", logged)
+	}
+}
+
+func TestCaptureUnknownLengthBodies(t *testing.T) {
+	for _, capture := range []bool{false, true} {
+		logger, buf := newTestLogger()
+		middleware := StructuredLogger(StructuredLoggerConfig{
+			Logger:                     logger,
+			LogRequestBody:             true,
+			MaxBodySize:                4,
+			CaptureUnknownLengthBodies: capture,
+		})
+
+		var received string
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+		req.ContentLength = -1
+		serve(req, "/", func(c *gin.Context) {
+			body, _ := io.ReadAll(c.Request.Body)
+			received = string(body)
+		}, middleware)
+
+		if received != "0123456789" {
+			t.Errorf("capture %v: handler received %q, want the full body", capture, received)
+		}
+
+		body, logged := buf.entries(t)[0]["request_body"]
+		if logged != capture || (capture && body != "0123") {
+			t.Errorf("capture %v: request_body = %v, want it bounded by MaxBodySize only when enabled", capture, body)
+		}
+	}
+}
+
+func TestDecodeRequestEncoding(t *testing.T) {
+	var gzipped, deflated bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write([]byte(`{"name":"gopher"}`))
+	gz.Close()
+	zw := zlib.NewWriter(&deflated)
+	zw.Write([]byte(`{"name":"gopher"}`))
+	zw.Close()
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+		want     string
+		err      bool
+	}{
+		{"gzip", "gzip", gzipped.Bytes(), `{"name":"gopher"}`, false},
+		{"deflate", "Deflate", deflated.Bytes(), `{"name":"gopher"}`, false},
+		{"identity", "", []byte("plain"), "plain", false},
+		{"corrupt", "gzip", []byte("not gzip"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			middleware := StructuredLogger(StructuredLoggerConfig{
+				Logger:                logger,
+				LogRequestBody:        true,
+				MaxBodySize:           1024,
+				DecodeRequestEncoding: true,
+			})
+
+			var received []byte
+			req := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(tt.body))
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			serve(req, "/", func(c *gin.Context) {
+				received, _ = io.ReadAll(c.Request.Body)
+			}, middleware)
+
+			if !bytes.Equal(received, tt.body) {
+				t.Errorf("handler received %q, want the original body", received)
+			}
+			entry := buf.entries(t)[0]
+			if tt.err {
+				if entry["request_body_error"] == nil || entry["request_body"] != nil {
+					t.Errorf("entry = %v, want request_body_error instead of the body", entry)
+				}
+			} else if entry["request_body"] != tt.want {
+				t.Errorf("request_body = %v, want %s", entry["request_body"], tt.want)
+			}
+		})
+	}
+}
+
+func TestLogBodyChecksum(t *testing.T) {
+	body := strings.Repeat("payload ", 64)
+	digest := sha256.Sum256([]byte(body))
+	want := hex.EncodeToString(digest[:])
+
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		limit   int64
+		logged  bool
+	}{
+		{"read by handler", func(c *gin.Context) { io.ReadAll(c.Request.Body) }, 16, true},
+		{"unread within limit", ok, 1024, true},
+		{"unread remainder at limit", ok, int64(len(body)), true},
+		{"unread beyond limit", ok, 16, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogBodyChecksum: true, MaxBodySize: tt.limit})
+
+			serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body)), "/", tt.handler, middleware)
+
+			checksum, logged := buf.entries(t)[0]["body_sha256"]
+			if logged != tt.logged || (logged && checksum != want) {
+				t.Errorf("body_sha256 = %v (logged %v), want %s logged %v", checksum, logged, want, tt.logged)
+			}
+		})
+	}
+}
+
+func TestSchemaValidator(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:      logger,
+		MaxBodySize: 1024,
+		SchemaValidator: func(c *gin.Context, body []byte) []error {
+			var payload map[string]any
+			if err := json.Unmarshal(body, &payload); err != nil {
+				return []error{err}
+			}
+			if _, ok := payload["name"]; !ok {
+				return []error{errors.New("name is required")}
+			}
+			return nil
+		},
+	})
+
+	var received []string
+	handler := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		received = append(received, string(body))
+		c.Status(http.StatusCreated)
+	}
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"name":"gopher"}`)), "/", handler, middleware)
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"title":"gopher"}`)), "/", handler, middleware)
+
+	if len(received) != 2 || received[1] != `{"title":"gopher"}` {
+		t.Fatalf("handler received %q, want both requests served with their bodies", received)
+	}
+
+	entries := buf.entries(t)
+	if _, ok := entries[0]["schema_errors"]; ok {
+		t.Errorf("schema_errors logged for a valid body: %v", entries[0])
+	}
+	schemaErrors, _ := entries[1]["schema_errors"].([]any)
+	if len(schemaErrors) != 1 || schemaErrors[0].(map[string]any)["error"] != "name is required" {
+		t.Errorf("schema_errors = %v, want the violation", entries[1]["schema_errors"])
+	}
+	if _, ok := entries[1]["request_body"]; ok || entries[1]["status"] != float64(http.StatusCreated) {
+		t.Errorf("entry = %v, want the request served without logging the body", entries[1])
+	}
+}
+
+func TestDecodeRequestBodyLimit(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write(bytes.Repeat([]byte("x"), 1<<20))
+	gz.Close()
+
+	body, err := decodeRequestBody(gzipped.Bytes(), "gzip", 16)
+	if !errors.Is(err, errDecompressionLimitExceeded) || len(body) != 16 {
+		t.Fatalf("decodeRequestBody() = %d bytes, %v, want the first 16 bytes and the limit error", len(body), err)
+	}
+	if size, ok := gzipDeclaredSize(gzipped.Bytes()); !ok || size != 1<<20 {
+		t.Errorf("gzipDeclaredSize() = %d, %v, want %d", size, ok, 1<<20)
+	}
+
+	body, err = decodeRequestBody(gzipped.Bytes(), "gzip", 1<<20)
+	if err != nil || len(body) != 1<<20 {
+		t.Errorf("decodeRequestBody() at the exact limit = %d bytes, %v, want the whole body", len(body), err)
+	}
+}
+
+func TestDecompressionLimitSecurityEvent(t *testing.T) {
+	var gzipped bytes.Buffer
+	gz := gzip.NewWriter(&gzipped)
+	gz.Write(bytes.Repeat([]byte("x"), 10000))
+	gz.Close()
+
+	logger, buf := newTestLogger()
+	security := SecurityLoggerWithConfig(SecurityLoggerConfig{Logger: logger})
+	structured := StructuredLogger(StructuredLoggerConfig{
+		Logger:                logger,
+		LogRequestBody:        true,
+		MaxBodySize:           256,
+		DecodeRequestEncoding: true,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(gzipped.Bytes()))
+	req.Header.Set("Content-Encoding", "gzip")
+	serve(req, "/upload", ok, security, structured)
+
+	event := findEntry(t, buf.entries(t), "Decompression limit exceeded")
+	if event["decompression_limit_exceeded"] != true || event["limit"] != float64(256) || event["declared_size"] != float64(10000) || event["content_encoding"] != "gzip" {
+		t.Errorf("event = %v, want the exceeded limit with the declared size", event)
+	}
+}
+
+// slowReader delays every read, like a client uploading slowly
+type slowReader struct {
+	io.Reader
+	delay time.Duration
+}
+
+func (r slowReader) Read(p []byte) (int, error) {
+	time.Sleep(r.delay)
+	return r.Reader.Read(p)
+}
+
+func TestLogBodyReadDuration(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogBodyReadDuration: true})
+
+	req := httptest.NewRequest(http.MethodPost, "/", slowReader{Reader: strings.NewReader("payload"), delay: 20 * time.Millisecond})
+	serve(req, "/", func(c *gin.Context) {
+		io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	}, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+
+	entries := buf.entries(t)
+	// One read returns the payload, another reports EOF
+	if duration, _ := entries[0]["body_read_duration"].(float64); duration < 0.04 {
+		t.Errorf("body_read_duration = %v, want at least the two 20ms reads", entries[0]["body_read_duration"])
+	}
+	if _, ok := entries[1]["body_read_duration"]; ok {
+		t.Errorf("body_read_duration logged without a body: %v", entries[1])
+	}
+}
+
+func TestLogBodyReadDurationAbortedCapture(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:                     logger,
+		LogRequestBody:             true,
+		LogBodyReadDuration:        true,
+		CaptureUnknownLengthBodies: true,
+	})
+
+	// A chunked upload trickling in one byte at a time, outlived by the context
+	body, writer := io.Pipe()
+	defer writer.Close()
+	go func() {
+		for {
+			time.Sleep(5 * time.Millisecond)
+			if _, err := writer.Write([]byte("x")); err != nil {
+				return
+			}
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest(http.MethodPost, "/", body).WithContext(ctx)
+	req.ContentLength = -1
+
+	serve(req, "/", func(c *gin.Context) {
+		c.Status(http.StatusRequestTimeout)
+	}, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Client error")
+	if entry["body_capture_aborted"] != "deadline_exceeded" {
+		t.Fatalf("body_capture_aborted = %v, want deadline_exceeded", entry["body_capture_aborted"])
+	}
+	if _, ok := entry["body_read_duration"]; !ok {
+		t.Errorf("body_read_duration missing: %v", entry)
+	}
+}
+
+func TestAbsoluteMaxBodySize(t *testing.T) {
+	previous := AbsoluteMaxBodySize
+	t.Cleanup(func() { AbsoluteMaxBodySize = previous })
+
+	AbsoluteMaxBodySize = 4
+	if got := bodyLimit(2); got != 2 {
+		t.Errorf("bodyLimit(2) = %d, want limits below the ceiling kept", got)
+	}
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:                     logger,
+		LogRequestBody:             true,
+		LogResponseBody:            true,
+		MaxBodySize:                1024 * 1024,
+		CaptureUnknownLengthBodies: true,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789"))
+	req.ContentLength = -1
+	recorder := serve(req, "/", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, "hello world")
+		if string(body) != "0123456789" {
+			t.Errorf("handler received %q, want the full body", body)
+		}
+	}, middleware)
+
+	if recorder.Body.String() != "hello world" {
+		t.Errorf("client received %q, want the full body", recorder.Body.String())
+	}
+	entry := buf.entries(t)[0]
+	if entry["request_body"] != "0123" || entry["response_body"] != "hell" {
+		t.Errorf("entry = %v, want both bodies capped at AbsoluteMaxBodySize", entry)
+	}
+
+	AbsoluteMaxBodySize = 0
+	if got := bodyLimit(1 << 40); got != 1<<40 {
+		t.Errorf("bodyLimit(1<<40) = %d, want no ceiling when disabled", got)
+	}
+}
+
+func TestStructuredJSONBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		nested      bool
+	}{
+		{"json object", "application/json", `{"name":"gopher","tags":["a","b"]}`, true},
+		{"json with charset", "application/json; charset=utf-8", `{"name":"gopher"}`, true},
+		{"invalid json", "application/json", `{"name":`, false},
+		{"not json", "text/plain", `{"name":"gopher"}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			middleware := StructuredLogger(StructuredLoggerConfig{
+				Logger:             logger,
+				LogRequestBody:     true,
+				StructuredJSONBody: true,
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			serve(req, "/", ok, middleware)
+
+			body := findEntry(t, buf.entries(t), "Request completed")["request_body"]
+			if tt.nested {
+				object, isObject := body.(map[string]any)
+				if !isObject || object["name"] != "gopher" {
+					t.Fatalf("request_body = %#v, want nested object", body)
+				}
+			} else if body != tt.body {
+				t.Fatalf("request_body = %#v, want string %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestStructuredJSONBodyArrayTruncation(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:               logger,
+		LogRequestBody:       true,
+		StructuredJSONBody:   true,
+		MaxBodyArrayElements: 2,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"ids":[1,2,3,4]}`))
+	req.Header.Set("Content-Type", "application/json")
+	serve(req, "/", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	ids := entry["request_body"].(map[string]any)["ids"].([]any)
+	if len(ids) != 3 || entry["body_array_truncated"] != true {
+		t.Fatalf("request_body ids = %v, body_array_truncated = %v", ids, entry["body_array_truncated"])
+	}
+}
+
+func TestContentLengthMismatch(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestBody: true}))
+	r.POST("/upload", ok)
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	// A client declaring 100 bytes but sending only 10 before closing its side,
+	// which net/http reports as an unexpected EOF while reading the body
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	io.WriteString(conn, "POST /upload HTTP/1.1\r\nHost: example.com\r\nContent-Length: 100\r\n\r\n0123456789")
+	conn.(*net.TCPConn).CloseWrite()
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	if entry["content_length_mismatch"] != true || entry["declared_content_length"] != float64(100) || entry["actual_content_length"] != float64(10) {
+		t.Fatalf("entry = %v, want a mismatch between 100 declared and 10 read bytes", entry)
+	}
+}
+
+func TestBodyCaptureStopsServerRead(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 20*time.Millisecond)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	})
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestBody: true}))
+
+	var readErr error
+	r.POST("/upload", func(c *gin.Context) {
+		_, readErr = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusRequestTimeout)
+	})
+
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	// A client sending part of its body and then stalling with the
+	// connection open, so only the read deadline can stop the capture
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	io.WriteString(conn, "POST /upload HTTP/1.1\r\nHost: example.com\r\nContent-Length: 100\r\n\r\n0123456789")
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if readErr == nil {
+		t.Fatal("handler read the body without error")
+	}
+	if entry := findEntry(t, buf.entries(t), "Client error"); entry["body_capture_aborted"] != "deadline_exceeded" {
+		t.Fatalf("body_capture_aborted = %v, want deadline_exceeded", entry["body_capture_aborted"])
+	}
+}
+
+func TestContentLengthMatch(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestBody: true})
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("0123456789")), "/", ok, middleware)
+
+	if entry := findEntry(t, buf.entries(t), "Request completed"); entry["content_length_mismatch"] != nil {
+		t.Fatalf("entry = %v, want no mismatch for a complete body", entry)
+	}
+}
@@ -0,0 +1,78 @@
+package ginlogger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// boostState tracks a temporary lowering of the effective log level
+type boostState struct {
+	mu    sync.RWMutex
+	level zapcore.Level
+	until time.Time
+	timer *time.Timer
+}
+
+var boost boostState
+
+// BoostLogging lowers the effective log level to level for duration, after
+// which the previous behavior is restored automatically. Calling it again
+// replaces any active boost; a non-positive duration ends the boost.
+//
+// Loggers created by this package (NewWriterLogger, WithHTTPOutput, ...) honor
+// the boosted level directly. Other loggers keep filtering by their own level,
+// so a boost can only enable entries they would accept anyway.
+func BoostLogging(level zapcore.Level, duration time.Duration) {
+	boost.mu.Lock()
+	defer boost.mu.Unlock()
+
+	if boost.timer != nil {
+		boost.timer.Stop()
+		boost.timer = nil
+	}
+
+	if duration <= 0 {
+		boost.until = time.Time{}
+		return
+	}
+
+	boost.level = level
+	boost.until = time.Now().Add(duration)
+	boost.timer = time.AfterFunc(duration, func() {
+		boost.mu.Lock()
+		defer boost.mu.Unlock()
+		if !time.Now().Before(boost.until) {
+			boost.until = time.Time{}
+		}
+	})
+}
+
+// BoostedLevel returns the boosted log level and whether a boost is active
+func BoostedLevel() (zapcore.Level, bool) {
+	boost.mu.RLock()
+	defer boost.mu.RUnlock()
+
+	if boost.until.IsZero() || !time.Now().Before(boost.until) {
+		return zapcore.InfoLevel, false
+	}
+	return boost.level, true
+}
+
+// boostEnabler enables levels allowed by an active boost. Otherwise the
+// runtime level override, when set, replaces the wrapped enabler.
+type boostEnabler struct {
+	zapcore.LevelEnabler
+}
+
+func (e boostEnabler) Enabled(level zapcore.Level) bool {
+	if boosted, ok := BoostedLevel(); ok && level >= boosted {
+		return true
+	}
+
+	if override, ok := overrideLevel(); ok {
+		return level >= override
+	}
+	return e.LevelEnabler.Enabled(level)
+}
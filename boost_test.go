@@ -0,0 +1,88 @@
+package ginlogger
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestBoostLogging(t *testing.T) {
+	t.Cleanup(func() { BoostLogging(zapcore.InfoLevel, 0) })
+
+	buf := &syncBuffer{}
+	logger := NewWriterLogger(buf, "info")
+
+	logAtLevel(logger, LevelDebug, "before boost")
+
+	BoostLogging(zapcore.DebugLevel, 100*time.Millisecond)
+	if level, active := BoostedLevel(); !active || level != zapcore.DebugLevel {
+		t.Fatalf("BoostedLevel() = %v, %v, want debug, true", level, active)
+	}
+	logAtLevel(logger, LevelDebug, "during boost")
+
+	time.Sleep(200 * time.Millisecond)
+	if _, active := BoostedLevel(); active {
+		t.Fatal("boost still active after its window")
+	}
+	logAtLevel(logger, LevelDebug, "after boost")
+
+	entries := buf.entries(t)
+	if len(entries) != 1 || entries[0]["msg"] != "during boost" {
+		t.Fatalf("entries = %v, want only the boosted one", entries)
+	}
+	// The entry keeps its real level
+	if entries[0]["level"] != "debug" {
+		t.Fatalf("level = %v, want debug", entries[0]["level"])
+	}
+}
+
+func TestBoostLoggingEndedEarly(t *testing.T) {
+	BoostLogging(zapcore.DebugLevel, time.Hour)
+	BoostLogging(zapcore.DebugLevel, 0)
+
+	if _, active := BoostedLevel(); active {
+		t.Fatal("boost still active after a non-positive duration")
+	}
+}
+
+func TestLogLevelOverrideKeepsEntryLevel(t *testing.T) {
+	t.Setenv(EnvLogLevel, "debug")
+	if err := ReloadFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		runtimeOverrides.Store(nil)
+	})
+
+	buf := &syncBuffer{}
+	logAtLevel(NewWriterLogger(buf, "info"), LevelDebug, "debug entry")
+
+	entries := buf.entries(t)
+	if len(entries) != 1 || entries[0]["level"] != "debug" {
+		t.Fatalf("entries = %v, want one debug entry", entries)
+	}
+}
+
+func TestBoostLoggingConcurrent(t *testing.T) {
+	t.Cleanup(func() { BoostLogging(zapcore.InfoLevel, 0) })
+
+	logger := NewWriterLogger(&syncBuffer{}, "info")
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				if i%2 == 0 {
+					BoostLogging(zapcore.DebugLevel, time.Duration(j)*time.Microsecond)
+				} else {
+					BoostedLevel()
+					logAtLevel(logger, LevelDebug, "concurrent")
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,55 @@
+package ginlogger
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// connInfoKey is the request context key of the per-connection state
+type connInfoKey struct{}
+
+type connInfo struct {
+	id       uint64
+	requests atomic.Uint64
+}
+
+var nextConnID atomic.Uint64
+
+// ConnContext is an http.Server ConnContext hook that assigns every accepted
+// connection an ID. Together with ConnStateMiddleware it reveals keep-alive
+// connection reuse:
+//
+//	srv := &http.Server{Handler: r, ConnContext: ginlogger.ConnContext}
+func ConnContext(ctx context.Context, _ net.Conn) context.Context {
+	return context.WithValue(ctx, connInfoKey{}, &connInfo{id: nextConnID.Add(1)})
+}
+
+// ConnStateMiddleware counts requests per connection and stores the connection
+// ID and the request number on that connection in the gin context. StructuredLogger
+// emits them as conn_id and conn_request_num. Requires ConnContext on the server.
+func ConnStateMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if info, ok := c.Request.Context().Value(connInfoKey{}).(*connInfo); ok {
+			c.Set(connIDKey, info.id)
+			c.Set(connRequestNumKey, info.requests.Add(1))
+		}
+		c.Next()
+	}
+}
+
+// connFields returns the connection fields stored by ConnStateMiddleware
+func connFields(c *gin.Context) []zap.Field {
+	id, ok := c.Get(connIDKey)
+	if !ok {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.Uint64("conn_id", id.(uint64)),
+		zap.Uint64("conn_request_num", c.GetUint64(connRequestNumKey)),
+	}
+}
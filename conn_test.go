@@ -0,0 +1,58 @@
+package ginlogger
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConnStateMiddleware(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(ConnStateMiddleware(), StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+	r.GET("/", ok)
+
+	server := httptest.NewUnstartedServer(r)
+	server.Config.ConnContext = ConnContext
+	server.Start()
+	defer server.Close()
+
+	get := func(client *http.Client) {
+		t.Helper()
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Drain the body so that the connection is reused
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	keepAlive := server.Client()
+	get(keepAlive)
+	get(keepAlive)
+	get(&http.Client{Transport: &http.Transport{DisableKeepAlives: true}})
+
+	entries := buf.entries(t)
+	if len(entries) != 3 {
+		t.Fatalf("entries = %v, want 3", entries)
+	}
+	if entries[0]["conn_id"] != entries[1]["conn_id"] || entries[0]["conn_request_num"] != float64(1) || entries[1]["conn_request_num"] != float64(2) {
+		t.Fatalf("keep-alive entries = %v, %v, want requests 1 and 2 on one connection", entries[0], entries[1])
+	}
+	if entries[2]["conn_id"] == entries[0]["conn_id"] || entries[2]["conn_request_num"] != float64(1) {
+		t.Fatalf("entry = %v, want the first request on a new connection", entries[2])
+	}
+}
+
+func TestConnStateMiddlewareWithoutConnContext(t *testing.T) {
+	logger, buf := newTestLogger()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, ConnStateMiddleware(), StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	if entry := findEntry(t, buf.entries(t), "Request completed"); entry["conn_id"] != nil {
+		t.Fatalf("entry = %v, want no connection fields without ConnContext", entry)
+	}
+}
@@ -0,0 +1,121 @@
+package ginlogger
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// Context keys used by handlers to pass request state to the middleware
+const (
+	requestStartKey   = "ginlogger.request_start"
+	coalescedKey      = "ginlogger.coalesced"
+	phasesKey         = "ginlogger.phases"
+	spansKey          = "ginlogger.spans"
+	subrequestsKey    = "ginlogger.subrequests"
+	uncompressedKey   = "ginlogger.uncompressed_size"
+	decompressionKey  = "ginlogger.decompression_limit"
+	timeoutKey        = "ginlogger.timeout"
+	suppressedKey     = "ginlogger.suppressed_fields"
+	panicRecoveredKey = "ginlogger.panic_recovered"
+	contextFieldsKey  = "ginlogger.fields"
+	connIDKey         = "ginlogger.conn_id"
+	connRequestNumKey = "ginlogger.conn_request_num"
+)
+
+// MarkCoalesced marks the request as coalesced with an identical in-flight
+// request (e.g. by a singleflight or caching layer). StructuredLogger then
+// emits coalesced: true for it.
+func MarkCoalesced(c *gin.Context) {
+	c.Set(coalescedKey, true)
+}
+
+// SetUncompressedSize records the response size before compression. Compression
+// middleware calls it so that StructuredLogger emits response_size (wire),
+// response_size_uncompressed and compression_ratio.
+func SetUncompressedSize(c *gin.Context, size int) {
+	c.Set(uncompressedKey, size)
+}
+
+// SuppressField removes the named field (e.g. "query" or "request_body") from
+// StructuredLogger's entry for this request. A trailing "*" matches field key
+// prefixes, as in VerboseFields.
+func SuppressField(c *gin.Context, field string) {
+	c.Set(suppressedKey, append(suppressedFields(c), field))
+}
+
+// suppressedFields returns the fields suppressed via SuppressField
+func suppressedFields(c *gin.Context) []string {
+	fields, _ := c.Get(suppressedKey)
+	result, _ := fields.([]string)
+	return result
+}
+
+// SetRequestTimeout records the timeout a timeout middleware applies to the
+// request. StructuredLogger emits it as timeout_configured, with
+// timed_out: true when the request took at least that long. Without it, the
+// deadline of the request context is used, if any.
+func SetRequestTimeout(c *gin.Context, timeout time.Duration) {
+	c.Set(timeoutKey, timeout)
+}
+
+// requestTimeout returns the timeout set via SetRequestTimeout or, failing
+// that, the time from start until the request context's deadline
+func requestTimeout(c *gin.Context, start time.Time) (time.Duration, bool) {
+	if value, ok := c.Get(timeoutKey); ok {
+		timeout, ok := value.(time.Duration)
+		return timeout, ok
+	}
+
+	if deadline, ok := c.Request.Context().Deadline(); ok {
+		return deadline.Sub(start).Round(time.Millisecond), true
+	}
+	return 0, false
+}
+
+// decompressionLimit describes a body that exceeded its decompression bound
+type decompressionLimit struct {
+	declaredSize int64
+	limit        int64
+}
+
+// ReportDecompressionLimitExceeded records that a request or response body
+// decompressed to more than limit bytes, a potential zip bomb. declaredSize is
+// the size announced by the sender, or -1 if unknown. SecurityLogger logs it
+// as a decompression_limit_exceeded event. StructuredLogger reports request
+// bodies it decodes itself (DecodeRequestEncoding).
+func ReportDecompressionLimitExceeded(c *gin.Context, declaredSize, limit int64) {
+	c.Set(decompressionKey, decompressionLimit{declaredSize: declaredSize, limit: limit})
+}
+
+// addedContextKeys returns the sorted keys of c not in before, omitting the
+// internal keys of this package
+func addedContextKeys(c *gin.Context, before map[string]bool) []string {
+	var added []string
+	for key := range c.Keys {
+		if !before[key] && !strings.HasPrefix(key, "ginlogger.") {
+			added = append(added, key)
+		}
+	}
+	slices.Sort(added)
+	return added
+}
+
+// addContextFields attaches fields to the request so that StructuredLogger
+// and LoggerFromContext include them
+func addContextFields(c *gin.Context, fields ...zap.Field) {
+	if len(fields) == 0 {
+		return
+	}
+	c.Set(contextFieldsKey, append(contextFields(c), fields...))
+}
+
+// contextFields returns the fields attached via addContextFields
+func contextFields(c *gin.Context) []zap.Field {
+	fields, _ := c.Get(contextFieldsKey)
+	result, _ := fields.([]zap.Field)
+	return result
+}
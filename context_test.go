@@ -0,0 +1,125 @@
+package ginlogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMarkCoalesced(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+	r.GET("/shared", func(c *gin.Context) {
+		MarkCoalesced(c)
+		c.Status(http.StatusOK)
+	})
+	r.GET("/own", ok)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/shared", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/own", nil))
+
+	entries := buf.entries(t)
+	if len(entries) != 2 || entries[0]["coalesced"] != true || entries[1]["coalesced"] != nil {
+		t.Fatalf("entries = %v, want coalesced only on the marked request", entries)
+	}
+}
+
+func TestSetUncompressedSize(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		// A compression middleware wrote 10 bytes for a 40 byte response
+		c.String(http.StatusOK, "compressed")
+		SetUncompressedSize(c, 40)
+	}, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+
+	entries := buf.entries(t)
+	if entries[0]["response_size"] != float64(10) || entries[0]["response_size_uncompressed"] != float64(40) || entries[0]["compression_ratio"] != float64(4) {
+		t.Errorf("entry = %v, want wire size 10, uncompressed 40 and ratio 4", entries[0])
+	}
+	if _, ok := entries[1]["compression_ratio"]; ok {
+		t.Errorf("compression fields logged without SetUncompressedSize: %v", entries[1])
+	}
+}
+
+func TestLogContextKeysAdded(t *testing.T) {
+	logger, buf := newTestLogger()
+	before := func(c *gin.Context) { c.Set("tenant", "acme") }
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogContextKeysAdded: true, LogContextKeyValues: true})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		c.Set("user_id", "user-7")
+		c.Set("cart_size", 3)
+		MarkCoalesced(c)
+		c.Status(http.StatusOK)
+	}, before, middleware)
+
+	entry := buf.entries(t)[0]
+	keys, _ := entry["context_keys_added"].([]any)
+	if len(keys) != 2 || keys[0] != "cart_size" || keys[1] != "user_id" {
+		t.Errorf("context_keys_added = %v, want the handler's keys without internal or earlier ones", entry["context_keys_added"])
+	}
+	values, _ := entry["context_values_added"].(map[string]any)
+	if values["user_id"] != "user-7" || values["cart_size"] != float64(3) {
+		t.Errorf("context_values_added = %v, want the added values", entry["context_values_added"])
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		SetRequestTimeout(c, time.Millisecond)
+		time.Sleep(5 * time.Millisecond)
+		c.Status(http.StatusServiceUnavailable)
+	}, middleware)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx), "/", ok, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+
+	entries := buf.entries(t)
+	if entries[0]["timeout_configured"] != 0.001 || entries[0]["timed_out"] != true {
+		t.Errorf("entry = %v, want a 1ms timeout that timed out", entries[0])
+	}
+	if entries[1]["timeout_configured"] != float64(3600) || entries[1]["timed_out"] != nil {
+		t.Errorf("entry = %v, want the context deadline without timed_out", entries[1])
+	}
+	if _, ok := entries[2]["timeout_configured"]; ok {
+		t.Errorf("timeout_configured logged without a timeout: %v", entries[2])
+	}
+}
+
+func TestSuppressField(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogLatencyNs: true})
+
+	serve(httptest.NewRequest(http.MethodGet, "/?token=secret", nil), "/", func(c *gin.Context) {
+		SuppressField(c, "query")
+		SuppressField(c, "latency*")
+		c.Status(http.StatusOK)
+	}, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/?token=secret", nil), "/", ok, middleware)
+
+	entries := buf.entries(t)
+	for _, key := range []string{"query", "latency", "latency_ns"} {
+		if _, ok := entries[0][key]; ok {
+			t.Errorf("%s logged after SuppressField: %v", key, entries[0])
+		}
+	}
+	if entries[0]["path"] != "/" {
+		t.Errorf("entry = %v, want the other fields kept", entries[0])
+	}
+	if entries[1]["query"] != "token=secret" || entries[1]["latency_ns"] == nil {
+		t.Errorf("entry = %v, want fields kept on requests without SuppressField", entries[1])
+	}
+}
@@ -0,0 +1,71 @@
+package ginlogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+type traceIDKey struct{}
+
+func TestDetachedLoggerOutlivesRequest(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	restore := SetTraceIDExtractor(func(ctx context.Context) string {
+		traceID, _ := ctx.Value(traceIDKey{}).(string)
+		return traceID
+	})
+	defer restore()
+
+	handled, done := make(chan struct{}), make(chan struct{})
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.POST("/reports", func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		addContextFields(c, zap.String("tenant", "acme"))
+
+		jobLogger := DetachedLogger(c)
+		go func() {
+			defer close(done)
+			// Runs after the request completed
+			<-handled
+			jobLogger.Info("Report generated")
+		}()
+		c.Status(http.StatusAccepted)
+	})
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "trace-1")
+	req := httptest.NewRequestWithContext(ctx, http.MethodPost, "/reports", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+	// A later request reuses the gin.Context
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/other", nil))
+	close(handled)
+	<-done
+
+	entry := findEntry(t, entries(), "Report generated")
+	for key, want := range map[string]string{"request_id": "req-1", "user_id": "user-1", "trace_id": "trace-1", "tenant": "acme"} {
+		if entry[key] != want {
+			t.Errorf("%s = %v, want %q", key, entry[key], want)
+		}
+	}
+}
+
+func TestDetachedLoggerPrefersContextTraceID(t *testing.T) {
+	restore := SetTraceIDExtractor(func(context.Context) string { return "extracted" })
+	defer restore()
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if got := requestTraceID(c); got != "extracted" {
+		t.Errorf("requestTraceID() = %q, want the extracted ID", got)
+	}
+
+	c.Set("trace_id", "set")
+	if got := requestTraceID(c); got != "set" {
+		t.Errorf("requestTraceID() = %q, want the gin context's ID", got)
+	}
+}
@@ -0,0 +1,103 @@
+package ginlogger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Default retention for bodies written by DumpBodiesToDir
+const (
+	defaultInlineBodyThreshold = 4 * 1024
+	defaultDumpMaxFiles        = 100
+)
+
+// dumpFilePrefix marks files written by bodyDumper, so pruning never touches
+// other files in the directory
+const dumpFilePrefix = "ginlogger-"
+
+// bodyDumper writes bodies too large to inline (or failed request captures)
+// into files and prunes old files
+type bodyDumper struct {
+	mu       sync.Mutex
+	dir      string
+	maxFiles int
+	maxAge   time.Duration
+}
+
+// dump writes content to a new file in the dump directory, named after
+// pattern as in os.CreateTemp, and returns its path
+func (d *bodyDumper) dump(pattern string, content string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := os.MkdirAll(d.dir, 0o700); err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(d.dir, dumpFilePrefix+pattern)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = file.WriteString(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return "", err
+	}
+
+	d.prune()
+	return file.Name(), nil
+}
+
+// prune removes dump files older than maxAge and the oldest files beyond
+// maxFiles. Errors are ignored, a later dump retries.
+func (d *bodyDumper) prune() {
+	entries, err := os.ReadDir(d.dir)
+	if err != nil {
+		return
+	}
+
+	type dumpFile struct {
+		path    string
+		modTime time.Time
+	}
+
+	var files []dumpFile
+	now := time.Now()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasPrefix(entry.Name(), dumpFilePrefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		path := filepath.Join(d.dir, entry.Name())
+		if d.maxAge > 0 && now.Sub(info.ModTime()) > d.maxAge {
+			os.Remove(path)
+			continue
+		}
+		files = append(files, dumpFile{path: path, modTime: info.ModTime()})
+	}
+
+	if len(files) <= d.maxFiles {
+		return
+	}
+
+	// Newest first, everything past maxFiles is removed
+	slices.SortFunc(files, func(a, b dumpFile) int {
+		return b.modTime.Compare(a.modTime)
+	})
+	for _, file := range files[d.maxFiles:] {
+		os.Remove(file.path)
+	}
+}
@@ -0,0 +1,94 @@
+package ginlogger
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestDumpBodiesToDir(t *testing.T) {
+	dir := t.TempDir()
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:              logger,
+		LogRequestBody:      true,
+		LogResponseBody:     true,
+		MaxBodySize:         1024,
+		DumpBodiesToDir:     dir,
+		InlineBodyThreshold: 8,
+	})
+	large := strings.Repeat("x", 100)
+	echo := func(c *gin.Context) { c.String(http.StatusOK, large) }
+
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("small")), "/", func(c *gin.Context) {
+		c.String(http.StatusOK, "tiny")
+	}, middleware)
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(large)), "/", echo, middleware)
+
+	entries := buf.entries(t)
+	if entries[0]["request_body"] != "small" || entries[0]["response_body"] != "tiny" || entries[0]["request_body_file"] != nil {
+		t.Errorf("entry = %v, want small bodies inlined", entries[0])
+	}
+
+	for _, key := range []string{"request_body_file", "response_body_file"} {
+		file, _ := entries[1][key].(string)
+		if filepath.Dir(file) != dir || !strings.HasPrefix(filepath.Base(file), dumpFilePrefix) {
+			t.Fatalf("%s = %q, want a file in %s", key, file, dir)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil || string(content) != large {
+			t.Errorf("%s content = %q, %v, want the body", key, content, err)
+		}
+	}
+	if _, ok := entries[1]["request_body"]; ok {
+		t.Errorf("dumped request body also inlined: %v", entries[1])
+	}
+}
+
+func TestBodyDumperPrune(t *testing.T) {
+	dir := t.TempDir()
+	unrelated := filepath.Join(dir, "keep.txt")
+	if err := os.WriteFile(unrelated, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	expired := filepath.Join(dir, dumpFilePrefix+"expired.body")
+	if err := os.WriteFile(expired, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(expired, old, old)
+	os.Chtimes(unrelated, old, old)
+
+	dumper := &bodyDumper{dir: dir, maxFiles: 2, maxAge: time.Hour}
+	var paths []string
+	for i := range 3 {
+		path, err := dumper.dump("request-*.body", fmt.Sprint(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Distinct modification times keep the pruning order deterministic
+		mtime := time.Now().Add(time.Duration(i-3) * time.Minute)
+		os.Chtimes(path, mtime, mtime)
+		paths = append(paths, path)
+	}
+	dumper.prune()
+
+	for path, want := range map[string]bool{
+		unrelated: true,
+		expired:   false,
+		paths[0]:  false,
+		paths[1]:  true,
+		paths[2]:  true,
+	} {
+		if _, err := os.Stat(path); (err == nil) != want {
+			t.Errorf("%s exists = %v, want %v", filepath.Base(path), err == nil, want)
+		}
+	}
+}
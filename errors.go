@@ -0,0 +1,35 @@
+package ginlogger
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// errorChain is the errors.Unwrap chain of an error, outermost first
+type errorChain []error
+
+// unwrapChain returns err followed by every error it wraps
+func unwrapChain(err error) errorChain {
+	var chain errorChain
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err)
+	}
+	return chain
+}
+
+func (chain errorChain) MarshalLogArray(encoder zapcore.ArrayEncoder) error {
+	for depth, err := range chain {
+		layer := zapcore.ObjectMarshalerFunc(func(encoder zapcore.ObjectEncoder) error {
+			encoder.AddInt("depth", depth)
+			encoder.AddString("message", err.Error())
+			encoder.AddString("type", fmt.Sprintf("%T", err))
+			return nil
+		})
+		if appendErr := encoder.AppendObject(layer); appendErr != nil {
+			return appendErr
+		}
+	}
+	return nil
+}
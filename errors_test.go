@@ -0,0 +1,48 @@
+package ginlogger
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+var errNotFound = errors.New("row not found")
+
+func TestErrorLoggerErrorChain(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	handler := func(c *gin.Context) {
+		c.Error(fmt.Errorf("load order: %w", fmt.Errorf("query orders: %w", errNotFound)))
+		c.Error(errors.New("plain failure"))
+		c.Status(http.StatusInternalServerError)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", handler, ErrorLogger())
+
+	logged := entries()
+	if len(logged) != 2 {
+		t.Fatalf("got %d entries, want one per error", len(logged))
+	}
+
+	chain, _ := logged[0]["error_chain"].([]any)
+	want := []string{"load order: query orders: row not found", "query orders: row not found", "row not found"}
+	if len(chain) != len(want) {
+		t.Fatalf("error_chain = %v, want %d layers", chain, len(want))
+	}
+	for i, layer := range chain {
+		layer := layer.(map[string]any)
+		if layer["depth"] != float64(i) || layer["message"] != want[i] {
+			t.Errorf("layer %d = %v, want %q", i, layer, want[i])
+		}
+	}
+	if chain[2].(map[string]any)["type"] != "*errors.errorString" {
+		t.Errorf("innermost type = %v, want *errors.errorString", chain[2])
+	}
+
+	if _, ok := logged[1]["error_chain"]; ok {
+		t.Errorf("error_chain logged for an unwrapped error: %v", logged[1])
+	}
+}
@@ -0,0 +1,85 @@
+package ginlogger
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// orderFields moves the fields named in order to the front, in that sequence.
+// Remaining fields keep their relative order. Since the zap JSON encoder
+// preserves insertion order, this yields a deterministic key order.
+func orderFields(fields []zap.Field, order []string) []zap.Field {
+	if len(order) == 0 {
+		return fields
+	}
+
+	position := make(map[string]int, len(order))
+	for i, key := range order {
+		if _, ok := position[key]; !ok {
+			position[key] = i
+		}
+	}
+
+	known := make([][]zap.Field, len(order))
+	ordered := make([]zap.Field, 0, len(fields))
+	var rest []zap.Field
+	for _, field := range fields {
+		if i, ok := position[field.Key]; ok {
+			known[i] = append(known[i], field)
+		} else {
+			rest = append(rest, field)
+		}
+	}
+
+	for _, group := range known {
+		ordered = append(ordered, group...)
+	}
+	return append(ordered, rest...)
+}
+
+// fieldMatcher matches field keys exactly, or by prefix for patterns ending in "*"
+type fieldMatcher struct {
+	keys     map[string]bool
+	prefixes []string
+}
+
+func newFieldMatcher(patterns []string) *fieldMatcher {
+	matcher := &fieldMatcher{keys: make(map[string]bool, len(patterns))}
+	for _, pattern := range patterns {
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			matcher.prefixes = append(matcher.prefixes, prefix)
+		} else {
+			matcher.keys[pattern] = true
+		}
+	}
+	return matcher
+}
+
+func (m *fieldMatcher) Match(key string) bool {
+	if m.keys[key] {
+		return true
+	}
+	for _, prefix := range m.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Empty reports whether the matcher matches nothing
+func (m *fieldMatcher) Empty() bool {
+	return len(m.keys) == 0 && len(m.prefixes) == 0
+}
+
+// removeFields returns fields without the ones matched by matcher
+func removeFields(fields []zap.Field, matcher *fieldMatcher) []zap.Field {
+	kept := fields[:0]
+	for _, field := range fields {
+		if !matcher.Match(field.Key) {
+			kept = append(kept, field)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,116 @@
+package ginlogger
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// keyOrder returns the top-level keys of a JSON object in encoding order
+func keyOrder(t *testing.T, line string) []string {
+	t.Helper()
+
+	decoder := json.NewDecoder(strings.NewReader(line))
+	if _, err := decoder.Token(); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, token.(string))
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return keys
+}
+
+func TestOrderFields(t *testing.T) {
+	fields := []zap.Field{
+		zap.String("method", "GET"),
+		zap.String("path", "/"),
+		zap.Int("status", 200),
+		zap.String("request_id", "req-1"),
+	}
+
+	var keys []string
+	for _, field := range orderFields(fields, []string{"request_id", "unknown", "status"}) {
+		keys = append(keys, field.Key)
+	}
+	if strings.Join(keys, ",") != "request_id,status,method,path" {
+		t.Fatalf("order = %v, want the listed fields first and the rest in their order", keys)
+	}
+}
+
+func TestFieldOrder(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, FieldOrder: []string{"status", "path", "method"}})
+
+	serve(httptest.NewRequest(http.MethodGet, "/users", nil), "/users", ok, middleware)
+
+	keys := keyOrder(t, strings.TrimSpace(buf.String()))
+	// level, timestamp, caller and msg are written by the encoder first
+	var logged []string
+	for _, key := range keys {
+		if key == "status" || key == "path" || key == "method" || key == "latency" {
+			logged = append(logged, key)
+		}
+	}
+	if strings.Join(logged, ",") != "status,path,method,latency" {
+		t.Fatalf("keys = %v, want status, path and method first", keys)
+	}
+}
+
+func TestRemoveFields(t *testing.T) {
+	fields := []zap.Field{
+		zap.String("method", "GET"),
+		zap.String("header_X-Debug", "on"),
+		zap.String("header_Accept", "*/*"),
+		zap.String("request_body", "{}"),
+		zap.Int("status", 200),
+	}
+
+	var keys []string
+	for _, field := range removeFields(fields, newFieldMatcher([]string{"header_*", "request_body"})) {
+		keys = append(keys, field.Key)
+	}
+	if strings.Join(keys, ",") != "method,status" {
+		t.Fatalf("kept = %v, want method and status", keys)
+	}
+
+	if !newFieldMatcher(nil).Empty() {
+		t.Error("matcher without patterns is not empty")
+	}
+}
+
+func TestVerboseFieldsOutsideSample(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:               logger,
+		LogHeaders:           []string{"X-Debug"},
+		VerboseFields:        []string{"header_*"},
+		DiagnosticSampleRate: NeverSample,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Debug", "on")
+	serve(req, "/", ok, middleware)
+
+	entry := buf.entries(t)[0]
+	if _, ok := entry["header_X-Debug"]; ok {
+		t.Errorf("verbose field logged outside the diagnostic sample: %v", entry)
+	}
+	if entry["status"] == nil {
+		t.Errorf("core field missing: %v", entry)
+	}
+}
@@ -0,0 +1,91 @@
+package ginlogger
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+)
+
+// flushLoggers holds the loggers passed to middleware configs, so that Flush
+// syncs them along with the global logger
+var flushLoggers struct {
+	mu      sync.Mutex
+	loggers []Logger
+}
+
+// registerFlush remembers logger for Flush. Nil and already registered
+// loggers are ignored.
+func registerFlush(logger Logger) {
+	if logger == nil {
+		return
+	}
+
+	flushLoggers.mu.Lock()
+	defer flushLoggers.mu.Unlock()
+
+	comparable := reflect.TypeOf(logger).Comparable()
+	for _, registered := range flushLoggers.loggers {
+		if comparable && registered == logger {
+			return
+		}
+	}
+	flushLoggers.loggers = append(flushLoggers.loggers, logger)
+}
+
+// Flush flushes any buffered log entries of the global logger and of the
+// loggers passed to middleware configs. Sync errors of terminals and pipes
+// (e.g. "sync /dev/stdout: invalid argument") are ignored, as there is
+// nothing to flush.
+func Flush() error {
+	flushLoggers.mu.Lock()
+	loggers := append([]Logger{GetLogger()}, flushLoggers.loggers...)
+	flushLoggers.mu.Unlock()
+
+	var errs []error
+	for _, logger := range loggers {
+		if err := ignoreUnsyncable(logger.Sync()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ignoreUnsyncable removes the errors returned when syncing a file that does
+// not support it, such as stdout attached to a terminal or pipe
+func ignoreUnsyncable(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, err := range joined.Unwrap() {
+			if err := ignoreUnsyncable(err); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTTY) {
+		return nil
+	}
+	return err
+}
+
+// FlushHandler returns a gin.HandlerFunc that flushes buffered log entries on demand.
+// It is intended for operators inspecting log files and should only be mounted
+// on a protected (admin) route group.
+func FlushHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := Flush(); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Status(http.StatusNoContent)
+	}
+}
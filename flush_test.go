@@ -0,0 +1,78 @@
+package ginlogger
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// syncCountingLogger counts Sync calls
+type syncCountingLogger struct {
+	Logger
+	syncs atomic.Int32
+}
+
+func (l *syncCountingLogger) Sync() error {
+	l.syncs.Add(1)
+	return nil
+}
+
+func TestBufferedEntriesWrittenOnFlush(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := NewBufferedWriterLogger(buf, "info", BufferOptions{FlushInterval: time.Hour})
+
+	logger.Info("buffered entry")
+	if buf.String() != "" {
+		t.Fatalf("entry written before Flush: %q", buf.String())
+	}
+
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush() = %v", err)
+	}
+	if !strings.Contains(buf.String(), "buffered entry") {
+		t.Fatalf("entry not written after Flush: %q", buf.String())
+	}
+}
+
+func TestFlushSyncsConfigLoggers(t *testing.T) {
+	logger := &syncCountingLogger{Logger: NewWriterLogger(&syncBuffer{}, "info")}
+	StructuredLogger(StructuredLoggerConfig{Logger: logger})
+
+	if err := Flush(); err != nil {
+		t.Fatalf("Flush() = %v", err)
+	}
+	if logger.syncs.Load() != 1 {
+		t.Fatalf("config logger synced %d times, want 1", logger.syncs.Load())
+	}
+}
+
+func TestFlushHandlerIgnoresUnsyncableStdout(t *testing.T) {
+	// Syncing stdout fails with EINVAL when it is a pipe or terminal
+	stdout := NewZapLogger(zap.New(newWriterCore(os.Stdout, zap.InfoLevel)))
+	GinLoggerWithConfig(GinLoggerConfig{Logger: stdout})
+
+	w := serve(httptest.NewRequest(http.MethodPost, "/flush", nil), "/flush", FlushHandler())
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusNoContent, w.Body)
+	}
+}
+
+func TestIgnoreUnsyncable(t *testing.T) {
+	unsyncable := &os.PathError{Op: "sync", Path: "/dev/stdout", Err: syscall.EINVAL}
+	other := errors.New("disk full")
+
+	if err := ignoreUnsyncable(unsyncable); err != nil {
+		t.Errorf("ignoreUnsyncable(EINVAL) = %v, want nil", err)
+	}
+	if err := ignoreUnsyncable(errors.Join(unsyncable, other)); err == nil || err.Error() != "disk full" {
+		t.Errorf("ignoreUnsyncable(joined) = %v, want disk full", err)
+	}
+}
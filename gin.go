@@ -1,15 +1,25 @@
 package ginlogger
 
 import (
-	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
 	"regexp"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
+// redactedValue replaces the value of sensitive fields
+const redactedValue = "[REDACTED]"
+
 // GinLogger returns a gin.HandlerFunc for logging HTTP requests
 func GinLogger() gin.HandlerFunc {
 	return GinLoggerWithConfig(GinLoggerConfig{})
@@ -24,6 +34,7 @@ type GinLoggerConfig struct {
 
 // GinLoggerWithConfig returns a gin.HandlerFunc using configs
 func GinLoggerWithConfig(config GinLoggerConfig) gin.HandlerFunc {
+	registerFlush(config.Logger)
 	logger := config.Logger
 	if logger == nil {
 		logger = GetLogger()
@@ -59,11 +70,11 @@ func GinLoggerWithConfig(config GinLoggerConfig) gin.HandlerFunc {
 		fields := []zap.Field{
 			zap.String("method", c.Request.Method),
 			zap.String("path", path),
-			zap.String("ip", c.ClientIP()),
+			zap.String("ip", clientIP(c)),
 			zap.String("user_agent", c.Request.UserAgent()),
 			zap.Int("status", c.Writer.Status()),
 			zap.Duration("latency", latency),
-			zap.Int("body_size", c.Writer.Size()),
+			zap.Int("body_size", responseSize(c)),
 			zap.Time("timestamp", timestamp),
 		}
 
@@ -97,44 +108,132 @@ func GinLoggerWithConfig(config GinLoggerConfig) gin.HandlerFunc {
 
 // RequestIDMiddleware adds a unique request ID to each request
 func RequestIDMiddleware() gin.HandlerFunc {
+	return RequestIDMiddlewareWithConfig(RequestIDConfig{})
+}
+
+// RequestIDConfig defines the config for RequestIDMiddleware
+type RequestIDConfig struct {
+	Logger Logger
+	// DetectDuplicates logs a warning when an incoming X-Request-ID was
+	// already seen within DuplicateWindow (e.g. a misbehaving retry loop)
+	DetectDuplicates bool
+	DuplicateWindow  time.Duration
+	// DuplicateCacheSize bounds the number of remembered request IDs
+	DuplicateCacheSize int
+}
+
+// RequestIDMiddlewareWithConfig returns a request ID middleware using configs
+func RequestIDMiddlewareWithConfig(config RequestIDConfig) gin.HandlerFunc {
+	registerFlush(config.Logger)
+	logger := config.Logger
+	if logger == nil {
+		logger = GetLogger()
+	}
+
+	if config.DuplicateWindow == 0 {
+		config.DuplicateWindow = time.Minute
+	}
+
+	if config.DuplicateCacheSize == 0 {
+		config.DuplicateCacheSize = 10000
+	}
+
+	var seen *seenCache
+	if config.DetectDuplicates {
+		seen = newSeenCache(config.DuplicateCacheSize, config.DuplicateWindow)
+	}
+
 	return func(c *gin.Context) {
 		requestID := c.GetHeader("X-Request-ID")
 		if requestID == "" {
 			requestID = generateRequestID()
+		} else if seen != nil && seen.Seen(requestID, time.Now()) {
+			logger.Warn("Duplicate request ID detected",
+				zap.String("method", c.Request.Method),
+				zap.String("path", c.Request.URL.Path),
+				zap.String("ip", clientIP(c)),
+				zap.String("request_id", requestID),
+				zap.Bool("duplicate_request_id", true),
+			)
 		}
 		c.Set("request_id", requestID)
+		c.Request = c.Request.WithContext(ContextWithRequestID(c.Request.Context(), requestID))
 		c.Header("X-Request-ID", requestID)
 		c.Next()
 	}
 }
 
-// ErrorLogger middleware logs errors that occur during request processing
+// ErrorLogger middleware logs errors that occur during request processing.
+// Wrapped errors additionally carry an error_chain with every unwrapped layer.
 func ErrorLogger() gin.HandlerFunc {
+	return ErrorLoggerWithConfig(ErrorLoggerConfig{})
+}
+
+// ErrorReporter forwards panics and server errors to an external error
+// tracker (see the sentry sub-package)
+type ErrorReporter interface {
+	// ReportPanic is called by RecoveryLogger with the recovered value
+	ReportPanic(c *gin.Context, recovered any)
+	// ReportError is called by ErrorLogger for each error of a 5xx response
+	ReportError(c *gin.Context, err error)
+}
+
+// ErrorLoggerConfig defines the config for ErrorLogger middleware
+type ErrorLoggerConfig struct {
+	Logger Logger
+	// Reporter receives the errors of 5xx responses. A 5xx response without
+	// attached errors is reported as an error naming the status, unless it
+	// comes from a panic recovered by RecoveryLogger.
+	Reporter ErrorReporter
+}
+
+// ErrorLoggerWithConfig returns an error logging middleware using configs
+func ErrorLoggerWithConfig(config ErrorLoggerConfig) gin.HandlerFunc {
+	registerFlush(config.Logger)
+
 	return func(c *gin.Context) {
 		c.Next()
 
+		logger := loggerOrGlobal(config.Logger)
+
 		// Log any errors that occurred
 		for _, err := range c.Errors {
 			fields := []zap.Field{
 				zap.String("method", c.Request.Method),
 				zap.String("path", c.Request.URL.Path),
-				zap.String("ip", c.ClientIP()),
+				zap.String("ip", clientIP(c)),
 				zap.Error(err.Err),
 			}
 
+			// Add every layer of wrapped errors
+			if chain := unwrapChain(err.Err); len(chain) > 1 {
+				fields = append(fields, zap.Array("error_chain", chain))
+			}
+
 			if requestID := c.GetString("request_id"); requestID != "" {
 				fields = append(fields, zap.String("request_id", requestID))
 			}
 
 			switch err.Type {
 			case gin.ErrorTypeBind:
-				GetLogger().Warn("Binding error", fields...)
+				logger.Warn("Binding error", fields...)
 			case gin.ErrorTypeRender:
-				GetLogger().Error("Rendering error", fields...)
+				logger.Error("Rendering error", fields...)
 			case gin.ErrorTypePublic:
-				GetLogger().Info("Public error", fields...)
+				logger.Info("Public error", fields...)
 			default:
-				GetLogger().Error("Internal error", fields...)
+				logger.Error("Internal error", fields...)
+			}
+		}
+
+		// Forward server errors to the error tracker. A recovered panic was
+		// already reported by RecoveryLogger, not as a bare 500.
+		if config.Reporter != nil && c.Writer.Status() >= 500 {
+			if len(c.Errors) == 0 && !c.GetBool(panicRecoveredKey) {
+				config.Reporter.ReportError(c, fmt.Errorf("%d %s", c.Writer.Status(), http.StatusText(c.Writer.Status())))
+			}
+			for _, err := range c.Errors {
+				config.Reporter.ReportError(c, err.Err)
 			}
 		}
 	}
@@ -142,33 +241,104 @@ func ErrorLogger() gin.HandlerFunc {
 
 // RecoveryLogger middleware recovers from panics and logs them
 func RecoveryLogger() gin.HandlerFunc {
+	return RecoveryLoggerWithConfig(RecoveryLoggerConfig{})
+}
+
+// RecoveryLoggerConfig defines the config for RecoveryLogger middleware
+type RecoveryLoggerConfig struct {
+	Logger Logger
+	// PanicFormatter renders the recovered value as the panic field, e.g.
+	// calling Error() or extracting codes from custom types. When nil the
+	// value is logged with zap.Any.
+	PanicFormatter func(recovered any) string
+	// Reporter receives every recovered panic
+	Reporter ErrorReporter
+}
+
+// RecoveryLoggerWithConfig returns a recovery middleware using configs
+func RecoveryLoggerWithConfig(config RecoveryLoggerConfig) gin.HandlerFunc {
+	registerFlush(config.Logger)
+
 	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
+		panicField := zap.Any("panic", recovered)
+		if config.PanicFormatter != nil {
+			panicField = zap.String("panic", config.PanicFormatter(recovered))
+		}
+
 		fields := []zap.Field{
 			zap.String("method", c.Request.Method),
 			zap.String("path", c.Request.URL.Path),
-			zap.String("ip", c.ClientIP()),
-			zap.Any("panic", recovered),
+			zap.String("ip", clientIP(c)),
+			panicField,
 		}
 
 		if requestID := c.GetString("request_id"); requestID != "" {
 			fields = append(fields, zap.String("request_id", requestID))
 		}
 
-		GetLogger().Error("Panic recovered", fields...)
+		loggerOrGlobal(config.Logger).Error("Panic recovered", fields...)
+		if config.Reporter != nil {
+			config.Reporter.ReportPanic(c, recovered)
+		}
+		c.Set(panicRecoveredKey, true)
 		c.AbortWithStatus(500)
 	})
 }
 
+// NoRouteLogger returns a handler for r.NoRoute that logs requests that did
+// not match any route, distinct from generic client errors. Entries carry
+// event=route_not_found for alerting.
+func NoRouteLogger() gin.HandlerFunc {
+	return routingFailureLogger("Route not found", "route_not_found")
+}
+
+// NoMethodLogger returns a handler for r.NoMethod that logs requests to a
+// known route with an unsupported method. Requires engine.HandleMethodNotAllowed.
+// Entries carry event=method_not_allowed.
+func NoMethodLogger() gin.HandlerFunc {
+	return routingFailureLogger("Method not allowed", "method_not_allowed")
+}
+
+func routingFailureLogger(msg, event string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := []zap.Field{
+			zap.String("event", event),
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
+			zap.String("ip", clientIP(c)),
+			zap.Int("status", c.Writer.Status()),
+		}
+
+		if requestID := c.GetString("request_id"); requestID != "" {
+			fields = append(fields, zap.String("request_id", requestID))
+		}
+
+		GetLogger().Warn(msg, fields...)
+	}
+}
+
 // RequestBodyLogger middleware logs request body (use with caution for large payloads)
 type RequestBodyLoggerConfig struct {
 	MaxBodySize int64
 	SkipPaths   []string
+	// LogLevel is the level bodies are logged at (default debug)
+	LogLevel string
+	// OnlyOnError logs the body only when the response status is >= 400
+	OnlyOnError bool
+	// CaptureUnknownLengthBodies captures bodies without a Content-Length
+	// (e.g. chunked uploads), bounded by MaxBodySize
+	CaptureUnknownLengthBodies bool
 }
 
 func RequestBodyLogger(config RequestBodyLoggerConfig) gin.HandlerFunc {
 	if config.MaxBodySize == 0 {
 		config.MaxBodySize = 1024 * 1024 // 1MB default
 	}
+	config.MaxBodySize = bodyLimit(config.MaxBodySize)
+
+	if config.LogLevel == "" {
+		config.LogLevel = LevelDebug
+	}
 
 	skipPaths := make(map[string]bool, len(config.SkipPaths))
 	for _, path := range config.SkipPaths {
@@ -181,13 +351,11 @@ func RequestBodyLogger(config RequestBodyLoggerConfig) gin.HandlerFunc {
 			return
 		}
 
-		if c.Request.Body != nil && c.Request.ContentLength <= config.MaxBodySize {
-			bodyBytes, err := io.ReadAll(c.Request.Body)
+		var fields []zap.Field
+		if shouldCaptureBody(c.Request, config.MaxBodySize, config.CaptureUnknownLengthBodies) {
+			bodyBytes, err := captureRequestBody(c, config.MaxBodySize)
 			if err == nil {
-				// Restore the body for further processing
-				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-				fields := []zap.Field{
+				fields = []zap.Field{
 					zap.String("method", c.Request.Method),
 					zap.String("path", c.Request.URL.Path),
 					zap.String("body", string(bodyBytes)),
@@ -197,16 +365,52 @@ func RequestBodyLogger(config RequestBodyLoggerConfig) gin.HandlerFunc {
 					fields = append(fields, zap.String("request_id", requestID))
 				}
 
-				GetLogger().Debug("Request body", fields...)
+				if !config.OnlyOnError {
+					logAtLevel(GetLogger(), config.LogLevel, "Request body", fields...)
+				}
 			}
 		}
 
 		c.Next()
+
+		// Error responses are only known once the handler has run
+		if config.OnlyOnError && fields != nil && c.Writer.Status() >= 400 {
+			fields = append(fields, zap.Int("status", c.Writer.Status()))
+			logAtLevel(GetLogger(), config.LogLevel, "Request body", fields...)
+		}
+	}
+}
+
+// requestIDGenerator overrides generateRequestID when set
+var requestIDGenerator atomic.Pointer[func() string]
+
+// SetRequestIDGenerator replaces the generator of new request IDs, e.g. with
+// SequentialRequestIDs for reproducible logs in tests. Passing nil restores
+// the default generator; the returned function restores the previous one.
+func SetRequestIDGenerator(generate func() string) (restore func()) {
+	var previous *func() string
+	if generate == nil {
+		previous = requestIDGenerator.Swap(nil)
+	} else {
+		previous = requestIDGenerator.Swap(&generate)
+	}
+	return func() { requestIDGenerator.Store(previous) }
+}
+
+// SequentialRequestIDs returns a generator of deterministic request IDs
+// (prefix-1, prefix-2, ...), for use with SetRequestIDGenerator
+func SequentialRequestIDs(prefix string) func() string {
+	var counter atomic.Uint64
+	return func() string {
+		return prefix + "-" + strconv.FormatUint(counter.Add(1), 10)
 	}
 }
 
-// Helper function to generate request ID
 func generateRequestID() string {
+	if generate := requestIDGenerator.Load(); generate != nil {
+		return (*generate)()
+	}
+
 	// Simple implementation - in production, consider using UUID
 	return time.Now().Format("20060102150405") + "-" + randomString(8)
 }
@@ -235,6 +439,8 @@ func LoggerFromContext(c *gin.Context) Logger {
 		fields = append(fields, zap.String("user_id", userID))
 	}
 
+	fields = append(fields, contextFields(c)...)
+
 	if len(fields) > 0 {
 		return logger.With(fields...)
 	}
@@ -242,6 +448,73 @@ func LoggerFromContext(c *gin.Context) Logger {
 	return logger
 }
 
+// traceIDExtractor reads the trace ID for DetachedLogger when set
+var traceIDExtractor atomic.Pointer[func(ctx context.Context) string]
+
+// SetTraceIDExtractor sets how DetachedLogger reads the trace ID of the span
+// active in the request context, e.g. ginotel.TraceID. A "trace_id" set on the
+// gin context takes precedence. Passing nil removes the extractor; the
+// returned function restores the previous one.
+func SetTraceIDExtractor(extract func(ctx context.Context) string) (restore func()) {
+	var previous *func(ctx context.Context) string
+	if extract == nil {
+		previous = traceIDExtractor.Swap(nil)
+	} else {
+		previous = traceIDExtractor.Swap(&extract)
+	}
+	return func() { traceIDExtractor.Store(previous) }
+}
+
+// requestTraceID returns the "trace_id" of the gin context, or the one read
+// by the SetTraceIDExtractor extractor
+func requestTraceID(c *gin.Context) string {
+	if traceID := c.GetString("trace_id"); traceID != "" {
+		return traceID
+	}
+
+	if extract := traceIDExtractor.Load(); extract != nil {
+		return (*extract)(c.Request.Context())
+	}
+	return ""
+}
+
+// DetachedLogger returns a logger carrying the request's correlation fields
+// (request_id, user_id, trace_id and fields attached by other middleware) for
+// work that outlives the request, such as background goroutines. The trace_id
+// is read with the SetTraceIDExtractor extractor.
+//
+// Unlike LoggerFromContext, which must only be called while the request is
+// being handled, DetachedLogger is meant to be called in the handler before
+// spawning the job: the fields are copied up front and the returned logger
+// keeps no reference to the gin.Context, which gin reuses for later requests.
+func DetachedLogger(c *gin.Context) Logger {
+	fields := []zap.Field{}
+
+	for _, key := range []string{"request_id", "user_id"} {
+		if value := c.GetString(key); value != "" {
+			fields = append(fields, zap.String(key, value))
+		}
+	}
+
+	if traceID := requestTraceID(c); traceID != "" {
+		fields = append(fields, zap.String("trace_id", traceID))
+	}
+
+	fields = append(fields, contextFields(c)...)
+
+	if len(fields) > 0 {
+		return GetLogger().With(fields...)
+	}
+
+	return GetLogger()
+}
+
+// PathLevelRule overrides the log level of successful requests whose path matches Path
+type PathLevelRule struct {
+	Path  *regexp.Regexp
+	Level string
+}
+
 // StructuredLogger middleware provides structured logging with customizable fields
 type StructuredLoggerConfig struct {
 	Logger          Logger
@@ -249,33 +522,380 @@ type StructuredLoggerConfig struct {
 	SkipPathRegexps []*regexp.Regexp
 	UTC             bool
 	LogHeaders      []string
+	RedactHeaders   []string
+	TruncateHeaders map[string]int
 	LogRequestBody  bool
+	// LogResponseBody emits response_body, up to MaxBodySize bytes
 	LogResponseBody bool
 	MaxBodySize     int64
 	LogUserAgent    bool
 	LogReferer      bool
 	LogClientIP     bool
 	CustomFields    func(*gin.Context) []zap.Field
+	// DisableDefaultFields omits the default fields (method, path, status,
+	// latency, body_size, timestamp, query, request_id and user_id) so that only
+	// custom and explicitly enabled fields are emitted. SkipPaths and status
+	// based leveling still apply.
+	DisableDefaultFields bool
+	// LogRequestStart emits an additional entry before the handler runs so
+	// that in-flight (or hung) requests are visible
+	LogRequestStart bool
+	// LogRequestLine emits the reconstructed request line
+	// (e.g. "GET /path?query HTTP/1.1") as request_line
+	LogRequestLine bool
+	// LogScheme emits scheme (from TLS or X-Forwarded-Proto) and LogHost
+	// emits host, to distinguish traffic across domains served by one binary
+	LogScheme bool
+	LogHost   bool
+	// RateLimitStatus (default 429) marks responses with rate_limited: true.
+	// They can be routed to a dedicated RateLimitLogger and/or RateLimitLevel.
+	RateLimitStatus int
+	RateLimitLogger Logger
+	RateLimitLevel  string
+	// MaxAllowedSize is the limit of the body size limiter in front of the
+	// handlers, logged as max_allowed_size on 413 responses. These are always
+	// marked with size_limit_exceeded and the declared_size.
+	MaxAllowedSize int64
+	// StructuredJSONBody logs JSON request bodies as nested objects rather
+	// than escaped strings, falling back to a string when parsing fails
+	StructuredJSONBody bool
+	// MaxBodyArrayElements truncates arrays in structured JSON bodies beyond
+	// this many elements and marks the entry with body_array_truncated
+	MaxBodyArrayElements int
+	// LogClientCert emits client_cert_subject and client_cert_serial of the
+	// mTLS client certificate, when one was presented
+	LogClientCert bool
+	// FieldOrder emits the named fields first, in the given order, followed by
+	// all other fields. Useful for golden-file tests and readability.
+	FieldOrder []string
+	// WarnNoResponse logs a warning when a handler finishes without writing
+	// any response or setting a status, which usually indicates a bug. A
+	// status set without a body (e.g. c.Status(204)) is a valid response.
+	WarnNoResponse bool
+	// BodySampleRate (0.0-1.0, see NeverSample) logs the request body for
+	// roughly that fraction of requests while metadata is logged for all of
+	// them. Unsampled bodies are not read, so error responses (>= 400) only
+	// include them when LogWhenResponseHeader is set, which keeps every body
+	// captured. Zero logs every body.
+	BodySampleRate float64
+	// LogFingerprint emits request_fingerprint, a hash of the request shape
+	// (method, route, header names, content type) for anomaly clustering
+	LogFingerprint bool
+	// MaxURLLength truncates the logged path and query when together they
+	// exceed this length and marks the entry with url_truncated (0 disables)
+	MaxURLLength int
+	// LogStatusText emits status_text (e.g. "Not Found") next to the status,
+	// omitted for non-standard codes
+	LogStatusText bool
+	// LogRouteMatched emits route_matched, false when the request fell
+	// through to NoRoute, which clearly shows routing misses
+	LogRouteMatched bool
+	// VerboseFields are diagnostic fields (e.g. "header_*", "request_body")
+	// only included for a DiagnosticSampleRate (0.0-1.0, see NeverSample)
+	// fraction of requests, or for all of them when it is zero. A trailing "*"
+	// matches field key prefixes. Core fields are always kept.
+	VerboseFields        []string
+	DiagnosticSampleRate float64
+	// LogWriteTimeout bounds how long a request waits for the log sink. On
+	// timeout an entry the sink has not started writing is dropped and
+	// counted in DroppedLogEntries (0 waits). Each logger is written by its
+	// own goroutine, so a stuck sink only delays its own entries.
+	LogWriteTimeout time.Duration
+	// DetectPII masks common PII (emails, phone numbers, IPs, card numbers,
+	// SSNs) in string fields and structured JSON bodies and emits
+	// pii_redacted_count. PIIPatterns replaces DefaultPIIPatterns when set.
+	DetectPII   bool
+	PIIPatterns []*regexp.Regexp
+	// PathLevelOverrides sets the level of responses below 400 by path, e.g.
+	// info for /admin audits and debug for /public. The first matching rule
+	// wins; error responses keep their status-based level.
+	PathLevelOverrides []PathLevelRule
+	// LogTTFB emits ttfb, the time until the first response byte was sent,
+	// distinguishing slow-to-start from slow-to-finish (e.g. streaming) responses
+	LogTTFB bool
+	// CaptureUnknownLengthBodies captures bodies without a Content-Length
+	// (e.g. chunked uploads), bounded by MaxBodySize
+	CaptureUnknownLengthBodies bool
+	// LogUptime emits server_uptime, the time since the process started, to
+	// correlate request behavior with the server lifecycle
+	LogUptime bool
+	// SampleRate (0.0-1.0, see NeverSample) logs roughly that fraction of
+	// successful requests; responses >= 400 are always logged. Zero logs
+	// every request.
+	SampleRate float64
+	// SampleKeyFunc derives a sampling key (e.g. the user ID) so requests
+	// sharing a key are consistently logged or dropped. Requests with an empty
+	// key are sampled randomly. A positive SampleKeyWindow rotates which keys
+	// are kept every window.
+	SampleKeyFunc   func(c *gin.Context) string
+	SampleKeyWindow time.Duration
+	// PerRouteSampleRate overrides SampleRate by route template (e.g.
+	// "/events/:id"), so high-volume routes can be sampled aggressively. Like
+	// SampleRate, zero logs every successful request and NeverSample none.
+	PerRouteSampleRate map[string]float64
+	// SummaryOnly suppresses per-request entries and instead logs a
+	// "Request summary" per route every SummaryInterval (default 1m) with the
+	// request count, error rate and latency percentiles. Shutdown logs the
+	// summary of the last, partial interval. SummaryLogErrors
+	// still logs responses >= 400 individually.
+	SummaryOnly      bool
+	SummaryInterval  time.Duration
+	SummaryLogErrors bool
+	// DumpBodiesToDir writes captured request and response bodies larger than
+	// InlineBodyThreshold (default 4KB) to files in this directory and logs
+	// their paths as request_body_file and response_body_file instead. Only
+	// the newest DumpMaxFiles (default 100) files younger than DumpMaxAge
+	// (0 keeps them) are retained. Dumped bodies are not PII-redacted.
+	DumpBodiesToDir     string
+	InlineBodyThreshold int
+	DumpMaxFiles        int
+	DumpMaxAge          time.Duration
+	// LogGoroutineID emits goroutine_id, the ID of the goroutine serving the
+	// request, for concurrency debugging. It captures a stack trace per
+	// request, so leave it off in production.
+	LogGoroutineID bool
+	// MaxLoggedHeaders caps the number of LogHeaders emitted per entry and
+	// marks entries exceeding it with headers_truncated (0 disables)
+	MaxLoggedHeaders int
+	// LogBodyChecksum emits body_sha256, the SHA-256 of the request body,
+	// hashed while the handler reads it. Unread remainders up to MaxBodySize
+	// are consumed after the handler; larger ones yield no checksum.
+	LogBodyChecksum bool
+	// LogWhenResponseHeader names a response header (e.g. "X-Debug-Log") that
+	// handlers set to force full logging of the request: the entry, its
+	// configured bodies and verbose fields bypass all sampling
+	LogWhenResponseHeader string
+	// LogRouteParams emits the matched path parameters as a route_params
+	// object (e.g. {"id": "123"}). RedactRouteParams are logged as [REDACTED].
+	LogRouteParams    bool
+	RedactRouteParams []string
+	// HangWatchdog logs a "Request still in flight" warning with in_flight_slow
+	// and the serving goroutine's stack when a request has not completed
+	// within this duration, so hung requests are visible (0 disables)
+	HangWatchdog time.Duration
+	// LogResponseTrailers emits the named response trailers (e.g.
+	// "Grpc-Status") as trailer_<name> fields when the handler set them
+	LogResponseTrailers []string
+	// LogQueryParams logs only these query parameters as a query_params
+	// object instead of the raw query, so unlisted (possibly secret)
+	// parameters never reach the logs. OmitQuery drops the raw query
+	// without logging any parameters.
+	LogQueryParams []string
+	OmitQuery      bool
+	// LogClockSkew emits clock_skew, the server receive time minus the time
+	// claimed by the client in ClockSkewHeader (default "Date"). HTTP dates,
+	// RFC 3339 and Unix seconds are understood; other values are ignored.
+	LogClockSkew    bool
+	ClockSkewHeader string
+	// SchemaValidator validates captured request bodies (e.g. against the
+	// route's JSON schema). Returned errors are logged as schema_errors
+	// without blocking the request, surfacing client contract drift.
+	SchemaValidator func(c *gin.Context, body []byte) []error
+	// RecentLogsCapacity keeps the last N entries of the middleware in memory
+	// for RecentLogsHandler (0 disables)
+	RecentLogsCapacity int
+	// SkipHead skips logging of HEAD requests, e.g. from monitoring systems
+	SkipHead bool
+	// CaptureFailedRequests writes a replayable curl command (method, full
+	// URL, headers and body up to MaxBodySize) for every 5xx response to
+	// FailedRequestWriter and/or a file in FailedRequestDir, logged as
+	// failed_request_file. RedactHeaders apply, and Authorization, Cookie and
+	// Proxy-Authorization are always redacted. Bodies that were not captured
+	// are flagged with body_omitted; DumpMaxFiles and DumpMaxAge bound the
+	// files kept.
+	CaptureFailedRequests bool
+	FailedRequestDir      string
+	FailedRequestWriter   io.Writer
+	// SetResponseTimeHeader adds the latency up to the first response byte as
+	// an X-Response-Time header (e.g. "45.123ms")
+	SetResponseTimeHeader bool
+	// SkipStatuses skips logging of responses with these status codes (e.g.
+	// 304 Not Modified), evaluated after the handler ran
+	SkipStatuses []int
+	// LogBodyReadDuration emits body_read_duration, the time spent reading the
+	// request body (by the middleware and the handler), which separates slow
+	// clients from slow processing
+	LogBodyReadDuration bool
+	// LogCacheValidators emits the response's ETag and Last-Modified headers
+	// as etag and last_modified, to debug why clients do or don't get 304s
+	LogCacheValidators bool
+	// LogCORS emits the request's origin, cors_allowed (whether the response's
+	// Access-Control-Allow-Origin admits it) and cors_allow_origin, to
+	// diagnose frontend CORS failures
+	LogCORS bool
+	// OnComplete is called after every request not skipped by SkipPaths,
+	// SkipPathRegexps or SkipHead has been logged (or sampled out), to feed
+	// custom metrics or alerting without parsing logs
+	OnComplete func(info RequestInfo)
+	// LogContextKeysAdded is a debug mode emitting context_keys_added, the
+	// gin context keys set by the handler chain, revealing middleware side
+	// effects. LogContextKeyValues adds their values as context_values_added.
+	LogContextKeysAdded bool
+	LogContextKeyValues bool
+	// LogLatencyNs and LogLatencyMs emit latency_ns (integer nanoseconds) and
+	// latency_ms (float milliseconds), numeric whatever the logger's duration
+	// encoder, so dashboards can rely on the type
+	LogLatencyNs bool
+	LogLatencyMs bool
+	// DetectRetries emits is_retry: true for client retries, recognized by an
+	// X-Retry-Count above zero or an Idempotency-Key already seen within
+	// RetryWindow (default 1m). At most RetryCacheSize (default 10000) keys
+	// are remembered.
+	DetectRetries  bool
+	RetryWindow    time.Duration
+	RetryCacheSize int
+	// LogServerTiming emits the metrics of the Server-Timing response header
+	// set by the handler (e.g. "db;dur=40, cache;dur=5") as a server_timing
+	// object of durations in milliseconds
+	LogServerTiming bool
+	// CaptureResponseOnError emits response_body only for 5xx responses,
+	// without buffering successful responses. Useful for intermittent errors.
+	CaptureResponseOnError bool
+	// LogRefererHost emits referer_host, only the host of the referer, which
+	// avoids leaking the referring page's path and query
+	LogRefererHost bool
+	// SlowSpanThresholds maps span names reported via RecordSpan to the
+	// duration above which a separate "Slow dependency" warning is logged
+	SlowSpanThresholds map[string]time.Duration
+	// DecodeRequestEncoding decompresses gzip and deflate request bodies
+	// (bounded by MaxBodySize) before logging them. Decoding failures are
+	// reported as request_body_error. Bodies decompressing beyond MaxBodySize
+	// are also reported to SecurityLogger as decompression_limit_exceeded.
+	DecodeRequestEncoding bool
 }
 
+// StructuredLogger returns the middleware for config. It panics if config is
+// invalid, see StructuredLoggerE for a variant returning the error.
 func StructuredLogger(config StructuredLoggerConfig) gin.HandlerFunc {
+	handler, err := StructuredLoggerE(config)
+	if err != nil {
+		panic("ginlogger: " + err.Error())
+	}
+	return handler
+}
+
+// StructuredLoggerE is like StructuredLogger but returns an error from
+// StructuredLoggerConfig.Validate instead of panicking
+func StructuredLoggerE(config StructuredLoggerConfig) (gin.HandlerFunc, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return structuredLogger(config), nil
+}
+
+func structuredLogger(config StructuredLoggerConfig) gin.HandlerFunc {
+	registerFlush(config.Logger)
+	registerFlush(config.RateLimitLogger)
 	logger := config.Logger
 	if logger == nil {
 		logger = GetLogger()
 	}
 
+	if config.RecentLogsCapacity > 0 {
+		logger = TeeLogger(logger, recentLogsLogger(config.RecentLogsCapacity))
+	}
+
+	// With LogWriteTimeout, each logger is written by its own goroutine
+	var queue, rateLimitQueue writeQueue
+
 	if config.MaxBodySize == 0 {
 		config.MaxBodySize = 1024 * 1024 // 1MB default
 	}
+	config.MaxBodySize = bodyLimit(config.MaxBodySize)
+
+	if config.RateLimitStatus == 0 {
+		config.RateLimitStatus = http.StatusTooManyRequests
+	}
 
 	skipPaths := make(map[string]bool, len(config.SkipPaths))
 	for _, path := range config.SkipPaths {
 		skipPaths[path] = true
 	}
 
+	skipStatuses := make(map[int]bool, len(config.SkipStatuses))
+	for _, status := range config.SkipStatuses {
+		skipStatuses[status] = true
+	}
+
+	// Redaction wins over truncation when a header is listed in both
+	redactHeaders := make(map[string]bool, len(config.RedactHeaders))
+	for _, header := range config.RedactHeaders {
+		redactHeaders[http.CanonicalHeaderKey(header)] = true
+	}
+
+	truncateHeaders := make(map[string]int, len(config.TruncateHeaders))
+	for header, length := range config.TruncateHeaders {
+		truncateHeaders[http.CanonicalHeaderKey(header)] = length
+	}
+
+	verboseFields := newFieldMatcher(config.VerboseFields)
+
+	redactRouteParams := make(map[string]bool, len(config.RedactRouteParams))
+	for _, param := range config.RedactRouteParams {
+		redactRouteParams[param] = true
+	}
+
+	if len(config.PIIPatterns) == 0 {
+		config.PIIPatterns = DefaultPIIPatterns
+	}
+
+	if config.ClockSkewHeader == "" {
+		config.ClockSkewHeader = "Date"
+	}
+
+	var dumper *bodyDumper
+	if config.DumpBodiesToDir != "" {
+		if config.InlineBodyThreshold <= 0 {
+			config.InlineBodyThreshold = defaultInlineBodyThreshold
+		}
+		if config.DumpMaxFiles <= 0 {
+			config.DumpMaxFiles = defaultDumpMaxFiles
+		}
+		dumper = &bodyDumper{
+			dir:      config.DumpBodiesToDir,
+			maxFiles: config.DumpMaxFiles,
+			maxAge:   config.DumpMaxAge,
+		}
+	}
+
+	var failedRequestDumper *bodyDumper
+	var failedRequestWriter io.Writer
+	if config.CaptureFailedRequests {
+		if config.FailedRequestDir != "" {
+			failedRequestDumper = &bodyDumper{
+				dir:      config.FailedRequestDir,
+				maxFiles: cmp.Or(config.DumpMaxFiles, defaultDumpMaxFiles),
+				maxAge:   config.DumpMaxAge,
+			}
+		}
+		if config.FailedRequestWriter != nil {
+			failedRequestWriter = &lockedWriter{w: config.FailedRequestWriter}
+		}
+	}
+
+	var idempotencyKeys *seenCache
+	if config.DetectRetries {
+		if config.RetryWindow <= 0 {
+			config.RetryWindow = time.Minute
+		}
+		if config.RetryCacheSize <= 0 {
+			config.RetryCacheSize = 10000
+		}
+		idempotencyKeys = newSeenCache(config.RetryCacheSize, config.RetryWindow)
+	}
+
+	var summaries *summaryAggregator
+	if config.SummaryOnly {
+		if config.SummaryInterval <= 0 {
+			config.SummaryInterval = time.Minute
+		}
+		summaries = newSummaryAggregator(logger, config.SummaryInterval)
+		registerWorker(summaries.Close)
+	}
+
 	return func(c *gin.Context) {
-		// Skip logging for specified paths
-		if skipPaths[c.Request.URL.Path] {
+		// Skip logging for specified paths and, if configured, HEAD requests
+		if skipPaths[c.Request.URL.Path] || (config.SkipHead && c.Request.Method == http.MethodHead) {
 			c.Next()
 			return
 		}
@@ -291,14 +911,126 @@ func StructuredLogger(config StructuredLoggerConfig) gin.HandlerFunc {
 		start := time.Now()
 		path := c.Request.URL.Path
 		raw := c.Request.URL.RawQuery
+		c.Set(requestStartKey, start)
+
+		urlTruncated := false
+		if config.MaxURLLength > 0 {
+			path, raw, urlTruncated = truncateURL(path, raw, config.MaxURLLength)
+		}
+
+		// Unsampled bodies are not read at all, unless a response header may
+		// still force the entry to full logging
+		bodySampled := sampled(config.BodySampleRate)
+
+		// Time body reads from here on, including the capture below
+		var bodyTiming *timingReader
+		if config.LogBodyReadDuration && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			bodyTiming = &timingReader{ReadCloser: c.Request.Body}
+			c.Request.Body = bodyTiming
+		}
 
 		// Capture request body if needed
-		var requestBody string
-		if config.LogRequestBody && c.Request.Body != nil && c.Request.ContentLength <= config.MaxBodySize {
-			bodyBytes, err := io.ReadAll(c.Request.Body)
-			if err == nil {
-				requestBody = string(bodyBytes)
-				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		var requestBody, bodyCaptureAborted string
+		var bodyDecodeErr error
+		var schemaErrors []error
+		var rawBody []byte
+		var bodyReadErr error
+		logBody := overrideLogBodies(config.LogRequestBody) && (bodySampled || config.LogWhenResponseHeader != "")
+		if (logBody || config.SchemaValidator != nil || config.CaptureFailedRequests) && shouldCaptureBody(c.Request, config.MaxBodySize, config.CaptureUnknownLengthBodies) {
+			bodyBytes, err := captureRequestBody(c, config.MaxBodySize)
+			rawBody, bodyReadErr = bodyBytes, err
+			if err == nil && config.DecodeRequestEncoding {
+				// The handler keeps the original, still compressed body
+				bodyBytes, bodyDecodeErr = decodeRequestBody(bodyBytes, c.GetHeader("Content-Encoding"), config.MaxBodySize)
+				if errors.Is(bodyDecodeErr, errDecompressionLimitExceeded) {
+					declaredSize := int64(-1)
+					// The trailer is only present in a completely captured body
+					if size, ok := gzipDeclaredSize(rawBody); ok && int64(len(rawBody)) < config.MaxBodySize {
+						declaredSize = size
+					}
+					ReportDecompressionLimitExceeded(c, declaredSize, config.MaxBodySize)
+				}
+			}
+
+			if err != nil {
+				bodyCaptureAborted = bodyCaptureAbortReason(err)
+			} else if bodyDecodeErr == nil {
+				if logBody {
+					requestBody = string(bodyBytes)
+				}
+				if config.SchemaValidator != nil && len(bodyBytes) > 0 {
+					schemaErrors = config.SchemaValidator(c, bodyBytes)
+				}
+			}
+		}
+
+		var checksum *checksumReader
+		if config.LogBodyChecksum && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			checksum = newChecksumReader(c.Request.Body)
+			c.Request.Body = checksum
+		}
+
+		var timing *timingWriter
+		if config.LogTTFB || config.SetResponseTimeHeader {
+			timing = &timingWriter{
+				ResponseWriter:     c.Writer,
+				start:              start,
+				responseTimeHeader: config.SetResponseTimeHeader,
+			}
+			c.Writer = timing
+		}
+
+		var responseCapture *bodyCaptureWriter
+		if config.LogResponseBody || config.CaptureResponseOnError {
+			responseCapture = &bodyCaptureWriter{
+				ResponseWriter:   c.Writer,
+				limit:            config.MaxBodySize,
+				onlyServerErrors: !config.LogResponseBody,
+			}
+			c.Writer = responseCapture
+		}
+
+		var statusTracker *statusTrackingWriter
+		if config.WarnNoResponse {
+			statusTracker = &statusTrackingWriter{ResponseWriter: c.Writer}
+			c.Writer = statusTracker
+		}
+
+		if config.LogRequestStart {
+			startFields := []zap.Field{
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+			}
+
+			if requestID := c.GetString("request_id"); requestID != "" {
+				startFields = append(startFields, zap.String("request_id", requestID))
+			}
+
+			logger.Info("Request started", startFields...)
+		}
+
+		if config.HangWatchdog > 0 {
+			stopWatchdog := startHangWatchdog(c, logger, config.HangWatchdog, path)
+			defer stopWatchdog()
+		}
+
+		// Idempotency keys are recorded for every request, sampled or not
+		isRetry := false
+		if idempotencyKeys != nil {
+			if count, err := strconv.Atoi(c.GetHeader("X-Retry-Count")); err == nil && count > 0 {
+				isRetry = true
+			}
+			if key := c.GetHeader("Idempotency-Key"); key != "" && idempotencyKeys.Seen(key, start) {
+				isRetry = true
+			}
+		}
+
+		// Snapshot the keys set so far to report what the handlers added
+		var keysBefore map[string]bool
+		if config.LogContextKeysAdded {
+			keysBefore = make(map[string]bool, len(c.Keys))
+			for key := range c.Keys {
+				keysBefore[key] = true
 			}
 		}
 
@@ -312,24 +1044,187 @@ func StructuredLogger(config StructuredLoggerConfig) gin.HandlerFunc {
 			timestamp = start.UTC()
 		}
 
+		// Gin writes the headers of empty responses after all handlers,
+		// bypassing the wrapped writer
+		if timing != nil {
+			timing.setResponseTime()
+		}
+
+		// Deferred so that it runs after logging, whichever way it returns
+		if config.OnComplete != nil {
+			defer config.OnComplete(newRequestInfo(c, latency))
+		}
+
+		recordRequestStatus(c.Writer.Status())
+
+		if statusTracker != nil && !statusTracker.statusSet && !c.Writer.Written() {
+			warnFields := []zap.Field{
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+				zap.String("route", c.FullPath()),
+				zap.Bool("no_response_written", true),
+			}
+
+			if requestID := c.GetString("request_id"); requestID != "" {
+				warnFields = append(warnFields, zap.String("request_id", requestID))
+			}
+
+			logger.Warn("No response written", warnFields...)
+		}
+
+		// Capture failed requests for replay, regardless of sampling
+		var failedRequestFile string
+		if config.CaptureFailedRequests && c.Writer.Status() >= 500 {
+			bodyState := replayBodyComplete
+			switch {
+			case rawBody == nil && c.Request.ContentLength != 0:
+				bodyState = replayBodyOmitted
+			case bodyReadErr != nil:
+				bodyState = replayBodyTruncated
+			case int64(len(rawBody)) >= config.MaxBodySize && c.Request.ContentLength != int64(len(rawBody)):
+				bodyState = replayBodyTruncated
+			}
+			capture := replayCommand(c, string(rawBody), bodyState, redactHeaders)
+
+			if failedRequestWriter != nil {
+				io.WriteString(failedRequestWriter, capture)
+			}
+			if failedRequestDumper != nil {
+				failedRequestFile, _ = failedRequestDumper.dump("failed-request-*.sh", capture)
+			}
+		}
+
+		// Slow dependency warnings are never sampled out
+		logSlowSpans(c, logger, config.SlowSpanThresholds)
+
+		if skipStatuses[c.Writer.Status()] {
+			return
+		}
+
+		// Handlers opt a request into full logging with a marker header
+		forced := config.LogWhenResponseHeader != "" && c.Writer.Header().Get(config.LogWhenResponseHeader) != ""
+		if forced {
+			bodySampled = true
+		}
+
+		if summaries != nil {
+			route := c.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			summaries.record(route, c.Writer.Status(), latency)
+
+			if !forced && (!config.SummaryLogErrors || c.Writer.Status() < 400) {
+				return
+			}
+		}
+
+		// Drop unsampled successful requests before building any fields
+		sampleRate := overrideSampleRate(config.SampleRate)
+		if rate, ok := config.PerRouteSampleRate[c.FullPath()]; ok {
+			sampleRate = rate
+		}
+		if !forced && c.Writer.Status() < 400 && !sampleRequest(c, sampleRate, config.SampleKeyFunc, config.SampleKeyWindow) {
+			return
+		}
+
 		// Build base fields
-		fields := []zap.Field{
-			zap.String("method", c.Request.Method),
-			zap.String("path", path),
-			zap.Int("status", c.Writer.Status()),
-			zap.Duration("latency", latency),
-			zap.Int("body_size", c.Writer.Size()),
-			zap.Time("timestamp", timestamp),
+		var fields []zap.Field
+		if !config.DisableDefaultFields {
+			fields = append(fields,
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+				zap.Int("status", c.Writer.Status()),
+				zap.Duration("latency", latency),
+				zap.Int("body_size", responseSize(c)),
+				zap.Time("timestamp", timestamp),
+			)
+
+			// Add query parameters unless replaced by the allowlist
+			if raw != "" && len(config.LogQueryParams) == 0 && !config.OmitQuery {
+				fields = append(fields, zap.String("query", raw))
+			}
 		}
 
-		// Add query parameters
-		if raw != "" {
-			fields = append(fields, zap.String("query", raw))
+		// Numeric latencies independent of the encoder's duration format
+		if config.LogLatencyNs {
+			fields = append(fields, zap.Int64("latency_ns", latency.Nanoseconds()))
+		}
+		if config.LogLatencyMs {
+			fields = append(fields, zap.Float64("latency_ms", float64(latency)/float64(time.Millisecond)))
+		}
+
+		// Add allowlisted query parameters
+		if len(config.LogQueryParams) > 0 {
+			if params, ok := allowedQueryParams(c.Request.URL.Query(), config.LogQueryParams); ok {
+				fields = append(fields, zap.Object("query_params", params))
+			}
+		}
+
+		if urlTruncated {
+			fields = append(fields, zap.Bool("url_truncated", true))
+		}
+
+		// Add time to first byte if measured
+		if timing != nil && !timing.firstByte.IsZero() {
+			fields = append(fields, zap.Duration("ttfb", timing.firstByte.Sub(start)))
+		}
+
+		if bodyTiming != nil {
+			fields = append(fields, zap.Duration("body_read_duration", bodyTiming.duration()))
+		}
+
+		// Add compression sizes if recorded by a compression middleware
+		if uncompressed := c.GetInt(uncompressedKey); uncompressed > 0 {
+			wire := c.Writer.Size()
+			fields = append(fields,
+				zap.Int("response_size", wire),
+				zap.Int("response_size_uncompressed", uncompressed),
+			)
+			if wire > 0 {
+				fields = append(fields, zap.Float64("compression_ratio", float64(uncompressed)/float64(wire)))
+			}
+		}
+
+		// Add client clock skew if enabled
+		if config.LogClockSkew {
+			if clientTime, ok := parseClientTime(c.GetHeader(config.ClockSkewHeader)); ok {
+				fields = append(fields, zap.Duration("clock_skew", start.Sub(clientTime)))
+			}
+		}
+
+		// Add goroutine ID if enabled
+		if config.LogGoroutineID {
+			if id := goroutineID(); id != 0 {
+				fields = append(fields, zap.Uint64("goroutine_id", id))
+			}
+		}
+
+		// Add server uptime if enabled
+		if config.LogUptime {
+			fields = append(fields, zap.Duration("server_uptime", time.Since(processStart)))
+		}
+
+		// Add matched path parameters if enabled
+		if config.LogRouteParams && len(c.Params) > 0 {
+			fields = append(fields, zap.Object("route_params", routeParams{params: c.Params, redact: redactRouteParams}))
+		}
+
+		// Add routing decision if enabled
+		if config.LogRouteMatched {
+			fields = append(fields, zap.Bool("route_matched", c.FullPath() != ""))
+		}
+
+		// Add status text if enabled
+		if config.LogStatusText {
+			if statusText := http.StatusText(c.Writer.Status()); statusText != "" {
+				fields = append(fields, zap.String("status_text", statusText))
+			}
 		}
 
 		// Add client IP if enabled
 		if config.LogClientIP {
-			fields = append(fields, zap.String("ip", c.ClientIP()))
+			fields = append(fields, zap.String("ip", clientIP(c)))
 		}
 
 		// Add user agent if enabled
@@ -344,26 +1239,247 @@ func StructuredLogger(config StructuredLoggerConfig) gin.HandlerFunc {
 			}
 		}
 
-		// Add specific headers
+		// Add referer host if enabled
+		if config.LogRefererHost {
+			if host := refererHost(c.Request); host != "" {
+				fields = append(fields, zap.String("referer_host", host))
+			}
+		}
+
+		// Add scheme and host if enabled
+		if config.LogScheme {
+			fields = append(fields, zap.String("scheme", requestScheme(c.Request)))
+		}
+
+		if config.LogHost {
+			fields = append(fields, zap.String("host", c.Request.Host))
+		}
+
+		// Add client certificate identity if enabled
+		if config.LogClientCert && c.Request.TLS != nil && len(c.Request.TLS.PeerCertificates) > 0 {
+			cert := c.Request.TLS.PeerCertificates[0]
+			fields = append(fields,
+				zap.String("client_cert_subject", cert.Subject.String()),
+				zap.String("client_cert_serial", cert.SerialNumber.String()),
+			)
+		}
+
+		// Add request fingerprint if enabled
+		if config.LogFingerprint {
+			fields = append(fields, zap.String("request_fingerprint", requestFingerprint(c.Request, c.FullPath())))
+		}
+
+		// Add request line if enabled
+		if config.LogRequestLine {
+			fields = append(fields, zap.String("request_line",
+				c.Request.Method+" "+c.Request.URL.RequestURI()+" "+c.Request.Proto))
+		}
+
+		// Add specific headers, at most MaxLoggedHeaders of them
+		loggedHeaders := 0
 		for _, header := range config.LogHeaders {
 			if value := c.Request.Header.Get(header); value != "" {
+				if config.MaxLoggedHeaders > 0 && loggedHeaders == config.MaxLoggedHeaders {
+					fields = append(fields, zap.Bool("headers_truncated", true))
+					break
+				}
+				loggedHeaders++
+
+				key := http.CanonicalHeaderKey(header)
+				if redactHeaders[key] {
+					value = redactedValue
+				} else if length, ok := truncateHeaders[key]; ok && length >= 0 && len(value) > length {
+					value = truncateUTF8(value, length)
+				}
 				fields = append(fields, zap.String("header_"+header, value))
 			}
 		}
 
-		// Add request body if captured
-		if requestBody != "" {
-			fields = append(fields, zap.String("request_body", requestBody))
+		// Add response trailers, declared or set with the Trailer: prefix
+		for _, trailer := range config.LogResponseTrailers {
+			value := c.Writer.Header().Get(trailer)
+			if value == "" {
+				value = c.Writer.Header().Get(http.TrailerPrefix + trailer)
+			}
+			if value != "" {
+				fields = append(fields, zap.String("trailer_"+trailer, value))
+			}
 		}
 
-		// Add request ID if available
-		if requestID := c.GetString("request_id"); requestID != "" {
-			fields = append(fields, zap.String("request_id", requestID))
+		if config.LogContextKeysAdded {
+			if added := addedContextKeys(c, keysBefore); len(added) > 0 {
+				fields = append(fields, zap.Strings("context_keys_added", added))
+				if config.LogContextKeyValues {
+					values := make(map[string]any, len(added))
+					for _, key := range added {
+						values[key] = c.Keys[key]
+					}
+					fields = append(fields, zap.Any("context_values_added", values))
+				}
+			}
 		}
 
-		// Add user ID if available
-		if userID := c.GetString("user_id"); userID != "" {
-			fields = append(fields, zap.String("user_id", userID))
+		if config.LogServerTiming {
+			if timing := parseServerTiming(c.Writer.Header().Values("Server-Timing")); len(timing) > 0 {
+				fields = append(fields, zap.Object("server_timing", timing))
+			}
+		}
+
+		if config.LogCacheValidators {
+			if etag := c.Writer.Header().Get("ETag"); etag != "" {
+				fields = append(fields, zap.String("etag", etag))
+			}
+			if lastModified := c.Writer.Header().Get("Last-Modified"); lastModified != "" {
+				fields = append(fields, zap.String("last_modified", lastModified))
+			}
+		}
+
+		// Only cross-origin requests carry an Origin
+		if origin := c.GetHeader("Origin"); config.LogCORS && origin != "" {
+			allowOrigin := c.Writer.Header().Get("Access-Control-Allow-Origin")
+			fields = append(fields,
+				zap.String("origin", origin),
+				zap.Bool("cors_allowed", allowOrigin == "*" || allowOrigin == origin),
+			)
+			if allowOrigin != "" {
+				fields = append(fields, zap.String("cors_allow_origin", allowOrigin))
+			}
+		}
+
+		// Large bodies are written to files and referenced by path
+		if dumper != nil && len(requestBody) > config.InlineBodyThreshold && (bodySampled || c.Writer.Status() >= 400) {
+			if file, err := dumper.dump("request-*.body", requestBody); err == nil {
+				fields = append(fields, zap.String("request_body_file", file))
+				requestBody = ""
+			}
+		}
+
+		// Add request body if captured and sampled. PII in structured bodies
+		// is redacted in the JSON values before the field is built.
+		piiRedacted := 0
+		if requestBody != "" && (bodySampled || c.Writer.Status() >= 400) {
+			var jsonBody json.RawMessage
+			var truncated, structured bool
+			if config.StructuredJSONBody {
+				jsonBody, truncated, structured = structuredJSONBody(requestBody, c.ContentType(), config.MaxBodyArrayElements)
+			}
+
+			if structured {
+				if config.DetectPII {
+					jsonBody, piiRedacted = redactJSONPII(jsonBody, config.PIIPatterns)
+				}
+				fields = append(fields, zap.Reflect("request_body", nestedJSON(jsonBody)))
+				if truncated {
+					fields = append(fields, zap.Bool("body_array_truncated", true))
+				}
+			} else {
+				fields = append(fields, zap.String("request_body", requestBody))
+			}
+		}
+
+		if bodyCaptureAborted != "" {
+			fields = append(fields, zap.String("body_capture_aborted", bodyCaptureAborted))
+		}
+
+		// A declared length differing from the bytes actually read indicates a
+		// truncated upload or a client bug. A body ending early fails the
+		// capture, which still keeps the bytes received so far in rawBody.
+		if rawBody != nil && c.Request.ContentLength >= 0 && int64(len(rawBody)) != c.Request.ContentLength {
+			fields = append(fields,
+				zap.Bool("content_length_mismatch", true),
+				zap.Int64("declared_content_length", c.Request.ContentLength),
+				zap.Int("actual_content_length", len(rawBody)),
+			)
+		}
+
+		if bodyDecodeErr != nil {
+			fields = append(fields, zap.String("request_body_error", bodyDecodeErr.Error()))
+		}
+
+		if failedRequestFile != "" {
+			fields = append(fields, zap.String("failed_request_file", failedRequestFile))
+		}
+
+		// Add schema violations, the request itself is never blocked
+		if len(schemaErrors) > 0 {
+			fields = append(fields, zap.Errors("schema_errors", schemaErrors))
+		}
+
+		// Add body checksum if the body could be hashed completely
+		if checksum != nil {
+			if sum, ok := checksum.sum(config.MaxBodySize); ok {
+				fields = append(fields, zap.String("body_sha256", sum))
+			}
+		}
+
+		// Add response body if captured
+		if responseCapture != nil && responseCapture.body.Len() > 0 {
+			responseBody := zap.String("response_body", responseCapture.body.String())
+			if dumper != nil && responseCapture.body.Len() > config.InlineBodyThreshold {
+				if file, err := dumper.dump("response-*.body", responseCapture.body.String()); err == nil {
+					responseBody = zap.String("response_body_file", file)
+				}
+			}
+
+			fields = append(fields, responseBody)
+			if responseCapture.truncated {
+				fields = append(fields, zap.Bool("response_body_truncated", true))
+			}
+		}
+
+		if !config.DisableDefaultFields {
+			// Add request ID if available
+			if requestID := c.GetString("request_id"); requestID != "" {
+				fields = append(fields, zap.String("request_id", requestID))
+			}
+
+			// Add user ID if available
+			if userID := c.GetString("user_id"); userID != "" {
+				fields = append(fields, zap.String("user_id", userID))
+			}
+		}
+
+		// Add connection reuse fields if ConnStateMiddleware is installed
+		fields = append(fields, connFields(c)...)
+
+		// Add fields attached by other middleware (e.g. JWTClaimsLogger)
+		fields = append(fields, contextFields(c)...)
+
+		// Add phase marks recorded by handlers
+		if phases, ok := phasesField(c); ok {
+			fields = append(fields, phases)
+		}
+
+		// Add downstream spans reported by handlers
+		if spans := recordedSpans(c); spans != nil {
+			fields = append(fields, zap.Array("spans", spans))
+		}
+
+		// Flag requests completing during graceful shutdown
+		if shuttingDown.Load() {
+			fields = append(fields, zap.Bool("during_shutdown", true))
+		}
+
+		// Explain why the request context was cancelled, if it was
+		if reason := cancellationReason(c.Request.Context()); reason != "" {
+			fields = append(fields, zap.String("cancellation_reason", reason))
+		}
+
+		if isRetry {
+			fields = append(fields, zap.Bool("is_retry", true))
+		}
+
+		// Distinguish a fired timeout from a request that was merely slow
+		if timeout, ok := requestTimeout(c, start); ok {
+			fields = append(fields, zap.Duration("timeout_configured", timeout))
+			if latency >= timeout {
+				fields = append(fields, zap.Bool("timed_out", true))
+			}
+		}
+
+		// Add coalescing marker if set by a handler
+		if c.GetBool(coalescedKey) {
+			fields = append(fields, zap.Bool("coalesced", true))
 		}
 
 		// Add custom fields if provided
@@ -372,22 +1488,86 @@ func StructuredLogger(config StructuredLoggerConfig) gin.HandlerFunc {
 			fields = append(fields, customFields...)
 		}
 
+		// Drop verbose diagnostics for requests outside the diagnostic sample
+		if !verboseFields.Empty() && !forced && !sampled(config.DiagnosticSampleRate) {
+			fields = removeFields(fields, verboseFields)
+		}
+
+		if suppressed := suppressedFields(c); len(suppressed) > 0 {
+			fields = removeFields(fields, newFieldMatcher(suppressed))
+		}
+
+		if config.DetectPII {
+			if count := piiRedacted + redactPII(fields, config.PIIPatterns); count > 0 {
+				fields = append(fields, zap.Int("pii_redacted_count", count))
+			}
+		}
+
 		// Log based on status code
-		switch {
-		case c.Writer.Status() >= 500:
-			logger.Error("Server error", fields...)
-		case c.Writer.Status() >= 400:
-			logger.Warn("Client error", fields...)
-		case c.Writer.Status() >= 300:
-			logger.Info("Redirection", fields...)
-		default:
-			logger.Info("Request completed", fields...)
+		level, msg := statusLevel(c.Writer.Status())
+		entryLogger, entryQueue := logger, &queue
+
+		if c.Writer.Status() < 400 {
+			for _, rule := range config.PathLevelOverrides {
+				if rule.Path.MatchString(c.Request.URL.Path) {
+					level = rule.Level
+					break
+				}
+			}
 		}
+
+		// Rate limit rejections are marked for abuse dashboards
+		if c.Writer.Status() == config.RateLimitStatus {
+			fields = append(fields, zap.Bool("rate_limited", true))
+			msg = "Rate limited"
+			if config.RateLimitLogger != nil {
+				entryLogger, entryQueue = config.RateLimitLogger, &rateLimitQueue
+			}
+			if config.RateLimitLevel != "" {
+				level = config.RateLimitLevel
+			}
+		}
+
+		// Body size limiter rejections reveal clients sending oversized payloads
+		if c.Writer.Status() == http.StatusRequestEntityTooLarge {
+			fields = append(fields, zap.Bool("size_limit_exceeded", true))
+			if c.Request.ContentLength >= 0 {
+				fields = append(fields, zap.Int64("declared_size", c.Request.ContentLength))
+			}
+			if config.MaxAllowedSize > 0 {
+				fields = append(fields, zap.Int64("max_allowed_size", config.MaxAllowedSize))
+			}
+			msg = "Request too large"
+		}
+
+		fields = append(orderFields(fields, config.FieldOrder), contextField(c.Request.Context()))
+		entryQueue.log(config.LogWriteTimeout, entryLogger, level, msg, fields...)
 	}
 }
 
 // PerformanceLogger middleware logs performance metrics
 func PerformanceLogger() gin.HandlerFunc {
+	return PerformanceLoggerWithConfig(PerformanceLoggerConfig{})
+}
+
+// PerformanceLoggerConfig defines the config for PerformanceLogger middleware
+type PerformanceLoggerConfig struct {
+	Logger Logger
+	// Threshold above which a request is logged as slow (default 1s)
+	Threshold time.Duration
+	// OnSlowRequest is called for every slow request after it was logged,
+	// e.g. to annotate the active trace span (see the otel sub-package)
+	OnSlowRequest func(c *gin.Context, latency time.Duration)
+}
+
+// PerformanceLoggerWithConfig returns a PerformanceLogger middleware using configs
+func PerformanceLoggerWithConfig(config PerformanceLoggerConfig) gin.HandlerFunc {
+	registerFlush(config.Logger)
+
+	if config.Threshold <= 0 {
+		config.Threshold = time.Second
+	}
+
 	return func(c *gin.Context) {
 		start := time.Now()
 
@@ -395,8 +1575,8 @@ func PerformanceLogger() gin.HandlerFunc {
 
 		latency := time.Since(start)
 
-		// Log slow requests (> 1 second)
-		if latency > time.Second {
+		// Log slow requests
+		if latency > config.Threshold {
 			fields := []zap.Field{
 				zap.String("method", c.Request.Method),
 				zap.String("path", c.Request.URL.Path),
@@ -408,13 +1588,76 @@ func PerformanceLogger() gin.HandlerFunc {
 				fields = append(fields, zap.String("request_id", requestID))
 			}
 
-			GetLogger().Warn("Slow request detected", fields...)
+			loggerOrGlobal(config.Logger).Warn("Slow request detected", fields...)
+
+			if config.OnSlowRequest != nil {
+				config.OnSlowRequest(c, latency)
+			}
 		}
 	}
 }
 
+// Attack patterns checked by SecurityLogger
+var (
+	sqlInjectionPattern  = regexp.MustCompile(`(?i)(union|select|insert|delete|drop|create|alter|exec|script)`)
+	xssPattern           = regexp.MustCompile(`(?i)(<script|javascript:|onload=|onerror=)`)
+	pathTraversalPattern = regexp.MustCompile(`\.\./`)
+)
+
 // SecurityLogger middleware logs security-related events
 func SecurityLogger() gin.HandlerFunc {
+	return SecurityLoggerWithConfig(SecurityLoggerConfig{})
+}
+
+// SecurityLoggerConfig defines the config for SecurityLogger middleware
+type SecurityLoggerConfig struct {
+	Logger Logger
+	// MaxURLLength flags URLs whose path plus query exceed this length (0 disables).
+	// The logged path is truncated to bound log size.
+	MaxURLLength int
+	// AbuseThreshold logs a "Possible abuse detected" warning with
+	// possible_abuse when a client IP receives this many 4xx responses within
+	// AbuseWindow (default 1m), e.g. brute-force logins producing 401s
+	// (0 disables). At most AbuseMaxTrackedIPs (default 10000) are tracked.
+	AbuseThreshold     int
+	AbuseWindow        time.Duration
+	AbuseMaxTrackedIPs int
+	// ThrottleWindow logs the "Suspicious request detected" warning for the
+	// same client IP and reason at most once per window, e.g. during scans.
+	// Suppressed warnings are counted and summarized every window (0
+	// disables). At most ThrottleMaxTracked (default 10000) pairs are tracked.
+	ThrottleWindow     time.Duration
+	ThrottleMaxTracked int
+}
+
+// SecurityLoggerWithConfig returns a SecurityLogger middleware using configs.
+// It panics if config is invalid.
+func SecurityLoggerWithConfig(config SecurityLoggerConfig) gin.HandlerFunc {
+	if err := config.Validate(); err != nil {
+		panic("ginlogger: " + err.Error())
+	}
+	registerFlush(config.Logger)
+
+	var abuse *abuseTracker
+	if config.AbuseThreshold > 0 {
+		if config.AbuseWindow <= 0 {
+			config.AbuseWindow = time.Minute
+		}
+		if config.AbuseMaxTrackedIPs <= 0 {
+			config.AbuseMaxTrackedIPs = 10000
+		}
+		abuse = newAbuseTracker(config.AbuseThreshold, config.AbuseWindow, config.AbuseMaxTrackedIPs)
+	}
+
+	var throttle *securityThrottle
+	if config.ThrottleWindow > 0 {
+		if config.ThrottleMaxTracked <= 0 {
+			config.ThrottleMaxTracked = 10000
+		}
+		throttle = newSecurityThrottle(loggerOrGlobal(config.Logger), config.ThrottleWindow, config.ThrottleMaxTracked)
+		registerWorker(throttle.Close)
+	}
+
 	return func(c *gin.Context) {
 		// Log suspicious patterns
 		userAgent := c.Request.UserAgent()
@@ -423,41 +1666,99 @@ func SecurityLogger() gin.HandlerFunc {
 		// Check for common attack patterns
 		suspicious := false
 		reason := ""
+		urlTruncated := false
 
 		// SQL injection patterns
-		if regexp.MustCompile(`(?i)(union|select|insert|delete|drop|create|alter|exec|script)`).MatchString(path) {
+		if sqlInjectionPattern.MatchString(path) {
 			suspicious = true
 			reason = "SQL injection attempt"
 		}
 
 		// XSS patterns
-		if regexp.MustCompile(`(?i)(<script|javascript:|onload=|onerror=)`).MatchString(path) {
+		if xssPattern.MatchString(path) {
 			suspicious = true
 			reason = "XSS attempt"
 		}
 
 		// Path traversal
-		if regexp.MustCompile(`\.\./`).MatchString(path) {
+		if pathTraversalPattern.MatchString(path) {
 			suspicious = true
 			reason = "Path traversal attempt"
 		}
 
-		if suspicious {
+		// Oversized URLs bloat logs and target parsers
+		if config.MaxURLLength > 0 {
+			path, _, urlTruncated = truncateURL(path, c.Request.URL.RawQuery, config.MaxURLLength)
+			if urlTruncated {
+				suspicious = true
+				reason = "Oversized URL"
+			}
+		}
+
+		if suspicious && (throttle == nil || throttle.Allow(clientIP(c), reason, time.Now())) {
 			fields := []zap.Field{
 				zap.String("method", c.Request.Method),
 				zap.String("path", path),
-				zap.String("ip", c.ClientIP()),
+				zap.String("ip", clientIP(c)),
 				zap.String("user_agent", userAgent),
 				zap.String("reason", reason),
 			}
 
+			if urlTruncated {
+				fields = append(fields, zap.Bool("url_truncated", true))
+			}
+
 			if requestID := c.GetString("request_id"); requestID != "" {
 				fields = append(fields, zap.String("request_id", requestID))
 			}
 
-			GetLogger().Warn("Suspicious request detected", fields...)
+			loggerOrGlobal(config.Logger).Warn("Suspicious request detected", fields...)
 		}
 
 		c.Next()
+
+		// Track client errors per IP once the status is known
+		if abuse != nil && c.Writer.Status() >= 400 && c.Writer.Status() < 500 {
+			if count, exceeded := abuse.Record(c.ClientIP(), time.Now()); exceeded {
+				fields := []zap.Field{
+					zap.Bool("possible_abuse", true),
+					zap.String("ip", clientIP(c)),
+					zap.Int("client_error_count", count),
+					zap.Duration("window", config.AbuseWindow),
+					zap.Int("status", c.Writer.Status()),
+					zap.String("method", c.Request.Method),
+					zap.String("path", path),
+				}
+
+				if requestID := c.GetString("request_id"); requestID != "" {
+					fields = append(fields, zap.String("request_id", requestID))
+				}
+
+				loggerOrGlobal(config.Logger).Warn("Possible abuse detected", fields...)
+			}
+		}
+
+		// Reported by StructuredLogger or handlers decompressing bodies
+		if value, ok := c.Get(decompressionKey); ok {
+			exceeded := value.(decompressionLimit)
+			fields := []zap.Field{
+				zap.Bool("decompression_limit_exceeded", true),
+				zap.Int64("limit", exceeded.limit),
+				zap.String("method", c.Request.Method),
+				zap.String("path", path),
+				zap.String("ip", clientIP(c)),
+				zap.String("content_encoding", c.GetHeader("Content-Encoding")),
+			}
+
+			if exceeded.declaredSize >= 0 {
+				fields = append(fields, zap.Int64("declared_size", exceeded.declaredSize))
+			}
+
+			if requestID := c.GetString("request_id"); requestID != "" {
+				fields = append(fields, zap.String("request_id", requestID))
+			}
+
+			loggerOrGlobal(config.Logger).Warn("Decompression limit exceeded", fields...)
+		}
 	}
 }
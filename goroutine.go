@@ -0,0 +1,26 @@
+package ginlogger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+)
+
+// goroutineID returns the ID of the calling goroutine, parsed from the
+// "goroutine N [running]:" header of its stack trace. It costs a stack
+// capture per call, so it is only meant for debugging.
+func goroutineID() uint64 {
+	var buf [64]byte
+	stack := buf[:runtime.Stack(buf[:], false)]
+
+	stack = bytes.TrimPrefix(stack, []byte("goroutine "))
+	if i := bytes.IndexByte(stack, ' '); i >= 0 {
+		stack = stack[:i]
+	}
+
+	id, err := strconv.ParseUint(string(stack), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
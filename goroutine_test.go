@@ -0,0 +1,37 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGoroutineID(t *testing.T) {
+	id := goroutineID()
+	if id == 0 || goroutineID() != id {
+		t.Fatalf("goroutineID() = %d, want a stable non-zero ID", id)
+	}
+
+	other := make(chan uint64)
+	go func() { other <- goroutineID() }()
+	if otherID := <-other; otherID == 0 || otherID == id {
+		t.Errorf("goroutineID() in another goroutine = %d, want a different ID than %d", otherID, id)
+	}
+}
+
+func TestLogGoroutineID(t *testing.T) {
+	logger, buf := newTestLogger()
+	var handlerID uint64
+	handler := func(c *gin.Context) {
+		handlerID = goroutineID()
+		c.Status(http.StatusOK)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", handler, StructuredLogger(StructuredLoggerConfig{Logger: logger, LogGoroutineID: true}))
+
+	if id := buf.entries(t)[0]["goroutine_id"]; id != float64(handlerID) {
+		t.Errorf("goroutine_id = %v, want the serving goroutine %d", id, handlerID)
+	}
+}
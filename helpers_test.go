@@ -0,0 +1,127 @@
+package ginlogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// entries decodes the JSON lines written so far
+func (b *syncBuffer) entries(t *testing.T) []map[string]any {
+	t.Helper()
+
+	var entries []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader([]byte(b.String())))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		var entry map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid log line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// newTestLogger returns a debug level Logger writing JSON lines to the returned buffer
+func newTestLogger() (Logger, *syncBuffer) {
+	buf := &syncBuffer{}
+	return NewWriterLogger(buf, "debug"), buf
+}
+
+// useFileGlobalLogger points the global logger at a JSON file for the test
+// and returns a function reading the entries written so far
+func useFileGlobalLogger(t *testing.T) func() []map[string]any {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "global.log")
+	config := ProductionConfigWithFile(file)
+	config.OutputPaths = []string{file}
+	if err := Initialize(config); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { Initialize(DefaultConfig()) })
+
+	return func() []map[string]any {
+		GetLogger().Sync()
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := &syncBuffer{}
+		buf.Write(data)
+		return buf.entries(t)
+	}
+}
+
+// findEntry returns the first entry with the given message, failing the test if there is none
+func findEntry(t *testing.T, entries []map[string]any, msg string) map[string]any {
+	t.Helper()
+
+	for _, entry := range entries {
+		if entry["msg"] == msg {
+			return entry
+		}
+	}
+	t.Fatalf("no %q entry in %v", msg, entries)
+	return nil
+}
+
+// countEntries returns how many entries written to buf have the given message
+func countEntries(t *testing.T, buf *syncBuffer, msg string) int {
+	t.Helper()
+
+	count := 0
+	for _, entry := range buf.entries(t) {
+		if entry["msg"] == msg {
+			count++
+		}
+	}
+	return count
+}
+
+// serve runs req through a gin engine with the given middleware and a handler on path
+func serve(req *http.Request, path string, handler gin.HandlerFunc, middleware ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	r := gin.New()
+	r.Use(middleware...)
+	r.Any(path, handler)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func ok(c *gin.Context) {
+	c.Status(http.StatusOK)
+}
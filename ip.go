@@ -0,0 +1,65 @@
+package ginlogger
+
+import (
+	"net/netip"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IPAnonymizationConfig defines how client IPs are masked before logging
+type IPAnonymizationConfig struct {
+	// AnonymizeIP zeroes the host portion of every logged ip field
+	AnonymizeIP bool
+	// IPv4MaskBits is the number of trailing IPv4 bits zeroed (default 8,
+	// the last octet)
+	IPv4MaskBits int
+	// IPv6MaskBits is the number of trailing IPv6 bits zeroed (default 80)
+	IPv6MaskBits int
+}
+
+var ipAnonymization atomic.Pointer[IPAnonymizationConfig]
+
+// SetIPAnonymization configures client IP masking for all middleware, keeping
+// coarse geolocation while not identifying individuals (e.g. for GDPR)
+func SetIPAnonymization(config IPAnonymizationConfig) {
+	if config.IPv4MaskBits <= 0 {
+		config.IPv4MaskBits = 8
+	}
+	if config.IPv6MaskBits <= 0 {
+		config.IPv6MaskBits = 80
+	}
+	ipAnonymization.Store(&config)
+}
+
+// clientIP returns the client IP to log, anonymized when configured
+func clientIP(c *gin.Context) string {
+	ip := c.ClientIP()
+
+	config := ipAnonymization.Load()
+	if config == nil || !config.AnonymizeIP {
+		return ip
+	}
+	return anonymizeIP(ip, config.IPv4MaskBits, config.IPv6MaskBits)
+}
+
+// anonymizeIP zeroes the trailing bits of ip. Unparseable values are redacted
+// rather than logged as is.
+func anonymizeIP(ip string, ipv4MaskBits, ipv6MaskBits int) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return redactedValue
+	}
+	addr = addr.Unmap().WithZone("")
+
+	maskBits := ipv6MaskBits
+	if addr.Is4() {
+		maskBits = ipv4MaskBits
+	}
+
+	prefix, err := addr.Prefix(max(addr.BitLen()-maskBits, 0))
+	if err != nil {
+		return redactedValue
+	}
+	return prefix.Addr().String()
+}
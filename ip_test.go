@@ -0,0 +1,45 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAnonymizeIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		v4   int
+		v6   int
+		want string
+	}{
+		{"203.0.113.42", 8, 80, "203.0.113.0"},
+		{"203.0.113.42", 16, 80, "203.0.0.0"},
+		{"::ffff:203.0.113.42", 8, 80, "203.0.113.0"},
+		{"2001:db8:85a3:1234:5678:8a2e:370:7334", 8, 80, "2001:db8:85a3::"},
+		{"fe80::1%eth0", 8, 80, "fe80::"},
+		{"203.0.113.42", 64, 80, "0.0.0.0"},
+		{"not-an-ip", 8, 80, redactedValue},
+		{"", 8, 80, redactedValue},
+	}
+
+	for _, tt := range tests {
+		if got := anonymizeIP(tt.ip, tt.v4, tt.v6); got != tt.want {
+			t.Errorf("anonymizeIP(%q, %d, %d) = %q, want %q", tt.ip, tt.v4, tt.v6, got, tt.want)
+		}
+	}
+}
+
+func TestSetIPAnonymization(t *testing.T) {
+	SetIPAnonymization(IPAnonymizationConfig{AnonymizeIP: true})
+	t.Cleanup(func() { ipAnonymization.Store(nil) })
+
+	logger, buf := newTestLogger()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "203.0.113.42:51234"
+	serve(req, "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger, LogClientIP: true}))
+
+	if ip := buf.entries(t)[0]["ip"]; ip != "203.0.113.0" {
+		t.Errorf("ip = %v, want the last octet zeroed by default", ip)
+	}
+}
@@ -0,0 +1,132 @@
+package ginlogger
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// JWTClaimsLoggerConfig defines the config for JWTClaimsLogger middleware
+type JWTClaimsLoggerConfig struct {
+	// ContextKey is the gin context key under which an upstream auth middleware
+	// stores the parsed claims as a map (default "jwt_claims")
+	ContextKey string
+	// Claims is the allowlist of claims to log (default sub, role and scope)
+	Claims []string
+	// RedactClaims are logged as [REDACTED], recording only their presence
+	RedactClaims []string
+	// LogExpiresIn emits jwt_expires_in, the time until the exp claim
+	// (negative once expired). A missing or invalid exp is skipped.
+	LogExpiresIn bool
+}
+
+// JWTClaimsLogger returns a middleware that adds selected JWT claims as
+// jwt_<claim> fields to the request's log entries. It only reads already
+// parsed claims and never logs the raw token. Place it after the auth middleware.
+func JWTClaimsLogger(config JWTClaimsLoggerConfig) gin.HandlerFunc {
+	if config.ContextKey == "" {
+		config.ContextKey = "jwt_claims"
+	}
+
+	if len(config.Claims) == 0 {
+		config.Claims = []string{"sub", "role", "scope"}
+	}
+
+	redactClaims := make(map[string]bool, len(config.RedactClaims))
+	for _, claim := range config.RedactClaims {
+		redactClaims[claim] = true
+	}
+
+	return func(c *gin.Context) {
+		value, ok := c.Get(config.ContextKey)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		claims := claimsMap(value)
+		var fields []zap.Field
+		for _, claim := range config.Claims {
+			claimValue, ok := claims[claim]
+			if !ok {
+				continue
+			}
+
+			if redactClaims[claim] {
+				fields = append(fields, zap.String("jwt_"+claim, redactedValue))
+			} else {
+				fields = append(fields, zap.Any("jwt_"+claim, claimValue))
+			}
+		}
+
+		if config.LogExpiresIn {
+			if expiresAt, ok := claimTime(claims["exp"]); ok {
+				fields = append(fields, zap.Duration("jwt_expires_in", time.Until(expiresAt)))
+			}
+		}
+
+		addContextFields(c, fields...)
+		c.Next()
+	}
+}
+
+// claimsMap converts map-shaped claims (e.g. map[string]any or jwt.MapClaims)
+// to a map[string]any
+func claimsMap(value any) map[string]any {
+	if claims, ok := value.(map[string]any); ok {
+		return claims
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	claims := make(map[string]any, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		claims[iter.Key().String()] = iter.Value().Interface()
+	}
+	return claims
+}
+
+// claimTime converts a NumericDate claim (seconds since the epoch, as decoded
+// by encoding/json or a JWT library) to a time
+func claimTime(value any) (time.Time, bool) {
+	var seconds float64
+	switch v := value.(type) {
+	case float64:
+		seconds = v
+	case int64:
+		seconds = float64(v)
+	case int:
+		seconds = float64(v)
+	case json.Number:
+		parsed, err := v.Float64()
+		if err != nil {
+			return time.Time{}, false
+		}
+		seconds = parsed
+	case time.Time:
+		return v, !v.IsZero()
+	case interface{ Unix() int64 }:
+		// e.g. *jwt.NumericDate
+		if reflect.ValueOf(v).Kind() == reflect.Pointer && reflect.ValueOf(v).IsNil() {
+			return time.Time{}, false
+		}
+		return time.Unix(v.Unix(), 0), true
+	default:
+		return time.Time{}, false
+	}
+
+	if math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds <= 0 {
+		return time.Time{}, false
+	}
+
+	whole, frac := math.Modf(seconds)
+	return time.Unix(int64(whole), int64(frac*1e9)), true
+}
@@ -0,0 +1,107 @@
+package ginlogger
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mapClaims mirrors jwt.MapClaims, a named map type
+type mapClaims map[string]any
+
+func TestJWTClaimsLogger(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	auth := func(c *gin.Context) {
+		c.Set("jwt_claims", mapClaims{"sub": "user-1", "role": "admin", "email": "a@example.com", "scope": "read"})
+		c.Next()
+	}
+	claimsLogger := JWTClaimsLogger(JWTClaimsLoggerConfig{RedactClaims: []string{"scope"}})
+
+	handler := func(c *gin.Context) {
+		LoggerFromContext(c).Info("Handled")
+		c.Status(http.StatusOK)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", handler,
+		StructuredLogger(StructuredLoggerConfig{}), auth, claimsLogger)
+
+	logged := entries()
+	for _, msg := range []string{"Handled", "Request completed"} {
+		entry := findEntry(t, logged, msg)
+		if entry["jwt_sub"] != "user-1" || entry["jwt_role"] != "admin" {
+			t.Errorf("%s: claims missing: %v", msg, entry)
+		}
+		if entry["jwt_scope"] != redactedValue {
+			t.Errorf("%s: jwt_scope = %v, want redacted", msg, entry["jwt_scope"])
+		}
+		if _, ok := entry["jwt_email"]; ok {
+			t.Errorf("%s: claim outside the allowlist logged: %v", msg, entry)
+		}
+	}
+}
+
+func TestJWTClaimsLoggerWithoutClaims(t *testing.T) {
+	logger, buf := newTestLogger()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok,
+		StructuredLogger(StructuredLoggerConfig{Logger: logger}), JWTClaimsLogger(JWTClaimsLoggerConfig{}))
+
+	for key := range buf.entries(t)[0] {
+		if len(key) > 4 && key[:4] == "jwt_" {
+			t.Errorf("%s logged without claims", key)
+		}
+	}
+}
+
+// numericDate mirrors jwt.NumericDate
+type numericDate struct{ time.Time }
+
+func TestClaimTime(t *testing.T) {
+	exp := time.Unix(1700000000, 0)
+	tests := []struct {
+		name  string
+		value any
+		ok    bool
+	}{
+		{"float64", float64(1700000000), true},
+		{"int64", int64(1700000000), true},
+		{"json.Number", json.Number("1700000000"), true},
+		{"time.Time", exp, true},
+		{"NumericDate", &numericDate{exp}, true},
+		{"nil NumericDate", (*numericDate)(nil), false},
+		{"missing", nil, false},
+		{"string", "1700000000", false},
+		{"NaN", math.NaN(), false},
+		{"negative", float64(-1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := claimTime(tt.value)
+			if ok != tt.ok || (ok && !got.Equal(exp)) {
+				t.Errorf("claimTime(%v) = %v, %v, want %v, %v", tt.value, got, ok, exp, tt.ok)
+			}
+		})
+	}
+}
+
+func TestJWTClaimsLoggerExpiresIn(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	auth := func(c *gin.Context) {
+		c.Set("jwt_claims", map[string]any{"sub": "user-1", "exp": float64(time.Now().Add(time.Hour).Unix())})
+		c.Next()
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok,
+		StructuredLogger(StructuredLoggerConfig{}), auth, JWTClaimsLogger(JWTClaimsLoggerConfig{LogExpiresIn: true}))
+
+	entry := findEntry(t, entries(), "Request completed")
+	expiresIn, _ := entry["jwt_expires_in"].(float64)
+	if expiresIn < 3590 || expiresIn > 3600 {
+		t.Errorf("jwt_expires_in = %v, want about an hour", entry["jwt_expires_in"])
+	}
+}
@@ -0,0 +1,133 @@
+// Package kafka ships gin-logger entries to Apache Kafka using
+// github.com/segmentio/kafka-go. It is a separate module so that applications
+// not logging to Kafka do not depend on a Kafka client.
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	ginlogger "github.com/csmart-libs/gin-logger"
+	"github.com/segmentio/kafka-go"
+)
+
+// Options configures shipping log entries to Kafka
+type Options struct {
+	// Level is the minimum level shipped to Kafka (default info)
+	Level string
+	// BatchSize is the number of entries per produce request (default 100)
+	BatchSize int
+	// FlushInterval is the maximum time an entry waits before being sent (default 1s)
+	FlushInterval time.Duration
+	// BufferSize bounds the number of queued entries (default 10000)
+	BufferSize int
+	// BlockWhenFull makes writes wait for buffer space, at most BlockTimeout
+	// (default 1s), when the brokers are unavailable or cannot keep up. By
+	// default new entries are dropped rather than slowing down requests.
+	BlockWhenFull bool
+	BlockTimeout  time.Duration
+	// MaxRetries is the number of retries of a failed batch (default 3,
+	// negative disables retries)
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, growing linearly (default 500ms)
+	RetryBackoff time.Duration
+	// ProduceTimeout bounds a single produce request (default 10s)
+	ProduceTimeout time.Duration
+	// Compression of the produced batches, e.g. kafka.Snappy (default none)
+	Compression kafka.Compression
+	// Transport configures the broker connections, e.g. a *kafka.Transport
+	// with TLS or SASL (default kafka.DefaultTransport)
+	Transport kafka.RoundTripper
+}
+
+// Writer batches log lines and produces them asynchronously to a Kafka topic.
+// Each entry is keyed by its request_id, so that all entries of a request land
+// in the same partition and keep their order.
+type Writer struct {
+	*ginlogger.BatchWriter
+	producer *kafka.Writer
+	timeout  time.Duration
+}
+
+// WithKafkaOutput returns a Logger that writes entries to base and also
+// produces them to topic on the Kafka cluster reachable through brokers
+// (e.g. []string{"kafka-1:9092", "kafka-2:9092"}). A nil base ships to Kafka
+// only.
+func WithKafkaOutput(base ginlogger.Logger, brokers []string, topic string, options Options) ginlogger.Logger {
+	return ginlogger.TeeLogger(base, ginlogger.NewWriterLogger(NewWriter(brokers, topic, options), options.Level))
+}
+
+// NewWriter returns a Writer producing to topic
+func NewWriter(brokers []string, topic string, options Options) *Writer {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Second
+	}
+
+	if options.ProduceTimeout <= 0 {
+		options.ProduceTimeout = 10 * time.Second
+	}
+
+	w := &Writer{
+		producer: &kafka.Writer{
+			Addr:  kafka.TCP(brokers...),
+			Topic: topic,
+			// Entries with the same key go to the same partition, unkeyed
+			// entries are spread round-robin
+			Balancer:     &kafka.Hash{},
+			BatchSize:    options.BatchSize,
+			BatchTimeout: time.Millisecond,
+			WriteTimeout: options.ProduceTimeout,
+			RequiredAcks: kafka.RequireOne,
+			// Retries are handled by the BatchWriter
+			MaxAttempts: 1,
+			Compression: options.Compression,
+			Transport:   options.Transport,
+		},
+		timeout: options.ProduceTimeout,
+	}
+	w.BatchWriter = ginlogger.NewBatchWriter(w.produce, ginlogger.BatchOptions{
+		BatchSize:     options.BatchSize,
+		FlushInterval: options.FlushInterval,
+		BufferSize:    options.BufferSize,
+		BlockWhenFull: options.BlockWhenFull,
+		BlockTimeout:  options.BlockTimeout,
+		MaxRetries:    options.MaxRetries,
+		RetryBackoff:  options.RetryBackoff,
+	})
+	return w
+}
+
+// Close produces the queued entries, stops the background producer and closes
+// the broker connections
+func (w *Writer) Close() error {
+	w.BatchWriter.Close()
+	return w.producer.Close()
+}
+
+// produce sends a batch of entries as one produce request
+func (w *Writer) produce(batch [][]byte) error {
+	messages := make([]kafka.Message, len(batch))
+	for i, entry := range batch {
+		messages[i] = kafka.Message{Key: requestIDKey(entry), Value: entry}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), w.timeout)
+	defer cancel()
+	return w.producer.WriteMessages(ctx, messages...)
+}
+
+// requestIDKey extracts the request_id of a JSON encoded entry
+func requestIDKey(entry []byte) []byte {
+	var fields struct {
+		RequestID string `json:"request_id"`
+	}
+	if json.Unmarshal(entry, &fields) != nil || fields.RequestID == "" {
+		return nil
+	}
+	return []byte(fields.RequestID)
+}
@@ -0,0 +1,185 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/kafka-go/protocol/metadata"
+	"github.com/segmentio/kafka-go/protocol/produce"
+	"go.uber.org/zap"
+)
+
+// record is a message received by mockBroker
+type record struct {
+	partition int32
+	key       string
+	value     string
+}
+
+// mockBroker is a kafka.RoundTripper standing in for a single node cluster
+// hosting one topic with several partitions
+type mockBroker struct {
+	topic      string
+	partitions int32
+	mu         sync.Mutex
+	records    []record
+	failures   int
+}
+
+func (b *mockBroker) RoundTrip(ctx context.Context, addr net.Addr, req kafka.Request) (kafka.Response, error) {
+	switch req := req.(type) {
+	case *metadata.Request:
+		partitions := make([]metadata.ResponsePartition, b.partitions)
+		for i := range partitions {
+			partitions[i] = metadata.ResponsePartition{PartitionIndex: int32(i), LeaderID: 1, ReplicaNodes: []int32{1}, IsrNodes: []int32{1}}
+		}
+		return &metadata.Response{
+			Brokers: []metadata.ResponseBroker{{NodeID: 1, Host: "localhost", Port: 9092}},
+			Topics:  []metadata.ResponseTopic{{Name: b.topic, Partitions: partitions}},
+		}, nil
+	case *produce.Request:
+		return b.produce(req)
+	default:
+		return nil, errors.New("unexpected request")
+	}
+}
+
+func (b *mockBroker) produce(req *produce.Request) (kafka.Response, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures > 0 {
+		b.failures--
+		return nil, errors.New("broker unavailable")
+	}
+
+	resp := &produce.Response{}
+	for _, topic := range req.Topics {
+		responseTopic := produce.ResponseTopic{Topic: topic.Topic}
+		for _, partition := range topic.Partitions {
+			for {
+				r, err := partition.RecordSet.Records.ReadRecord()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					return nil, err
+				}
+				key, _ := protocol.ReadAll(r.Key)
+				value, _ := protocol.ReadAll(r.Value)
+				b.records = append(b.records, record{partition: partition.Partition, key: string(key), value: string(value)})
+			}
+			responseTopic.Partitions = append(responseTopic.Partitions, produce.ResponsePartition{Partition: partition.Partition})
+		}
+		resp.Topics = append(resp.Topics, responseTopic)
+	}
+	return resp, nil
+}
+
+func (b *mockBroker) received() []record {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]record(nil), b.records...)
+}
+
+func TestWriterProducesKeyedEntries(t *testing.T) {
+	broker := &mockBroker{topic: "logs", partitions: 4}
+	w := NewWriter([]string{"localhost:9092"}, "logs", Options{Transport: broker, FlushInterval: time.Hour})
+
+	entries := []string{
+		`{"msg":"Request started","request_id":"req-1"}`,
+		`{"msg":"Request completed","request_id":"req-1"}`,
+		`{"msg":"Request completed","request_id":"req-2"}`,
+		`{"msg":"Cache warmed"}`,
+	}
+	for _, entry := range entries {
+		w.Write([]byte(entry))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+
+	records := broker.received()
+	if len(records) != len(entries) {
+		t.Fatalf("received %d records, want %d", len(records), len(entries))
+	}
+
+	// Records are grouped by partition, so match them by value
+	keys := make(map[string]string)
+	partitions := make(map[string]int32)
+	var order []string
+	for _, r := range records {
+		keys[r.value] = r.key
+		if r.key == "req-1" {
+			order = append(order, r.value)
+		}
+		if partition, ok := partitions[r.key]; ok && r.key != "" && partition != r.partition {
+			t.Errorf("entries of %s produced to partitions %d and %d", r.key, partition, r.partition)
+		}
+		partitions[r.key] = r.partition
+	}
+
+	want := map[string]string{entries[0]: "req-1", entries[1]: "req-1", entries[2]: "req-2", entries[3]: ""}
+	for value, key := range want {
+		if got, ok := keys[value]; !ok || got != key {
+			t.Errorf("record %s has key %q (received: %v), want %q", value, got, ok, key)
+		}
+	}
+	if len(order) != 2 || order[0] != entries[0] {
+		t.Errorf("req-1 records = %v, want them in write order", order)
+	}
+}
+
+func TestWithKafkaOutput(t *testing.T) {
+	broker := &mockBroker{topic: "logs", partitions: 2}
+	logger := WithKafkaOutput(nil, []string{"localhost:9092"}, "logs", Options{Transport: broker, Level: "warn"})
+
+	logger.Info("filtered")
+	logger.Warn("Slow request", zap.String("request_id", "req-1"), zap.Int("status", 200))
+	logger.Sync()
+
+	records := broker.received()
+	if len(records) != 1 {
+		t.Fatalf("received %v, want only the warning", records)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(records[0].value), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["msg"] != "Slow request" || entry["level"] != "warn" || entry["status"] != float64(200) || records[0].key != "req-1" {
+		t.Fatalf("record = %v with key %q, want the warning keyed by req-1", entry, records[0].key)
+	}
+}
+
+func TestWriterRetriesUnavailableBrokers(t *testing.T) {
+	broker := &mockBroker{topic: "logs", partitions: 1, failures: 2}
+	w := NewWriter([]string{"localhost:9092"}, "logs", Options{Transport: broker, RetryBackoff: time.Millisecond})
+
+	w.Write([]byte(`{"msg":"Request completed"}`))
+	w.Close()
+
+	if len(broker.received()) != 1 || w.Dropped() != 0 {
+		t.Fatalf("received %d records, dropped %d, want the entry delivered after retries", len(broker.received()), w.Dropped())
+	}
+}
+
+func TestWriterDropsWhenBrokersFail(t *testing.T) {
+	broker := &mockBroker{topic: "logs", partitions: 1, failures: 10}
+	w := NewWriter([]string{"localhost:9092"}, "logs", Options{Transport: broker, MaxRetries: 1, RetryBackoff: time.Millisecond})
+
+	w.Write([]byte(`{"msg":"Request completed"}`))
+	w.Close()
+
+	if len(broker.received()) != 0 || w.Dropped() != 1 {
+		t.Fatalf("received %d records, dropped %d, want the entry dropped", len(broker.received()), w.Dropped())
+	}
+}
@@ -0,0 +1,43 @@
+package ginlogger
+
+import (
+	"os"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// kubernetesEnv maps downward-API environment variables to field names
+var kubernetesEnv = []struct {
+	env   string
+	field string
+}{
+	{"POD_NAME", "pod_name"},
+	{"POD_NAMESPACE", "namespace"},
+	{"NODE_NAME", "node_name"},
+}
+
+// kubernetesFields reads the pod metadata once; unset variables are omitted
+var kubernetesFields = sync.OnceValue(func() []zap.Field {
+	var fields []zap.Field
+	for _, kv := range kubernetesEnv {
+		if value := os.Getenv(kv.env); value != "" {
+			fields = append(fields, zap.String(kv.field, value))
+		}
+	}
+	return fields
+})
+
+// WithKubernetesMetadata returns a Logger that adds pod_name, namespace and
+// node_name to every entry of base (the global logger when nil). The values
+// are read once from the POD_NAME, POD_NAMESPACE and NODE_NAME variables,
+// typically set via the Kubernetes downward API.
+func WithKubernetesMetadata(base Logger) Logger {
+	base = loggerOrGlobal(base)
+
+	fields := kubernetesFields()
+	if len(fields) == 0 {
+		return base
+	}
+	return base.With(fields...)
+}
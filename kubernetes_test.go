@@ -0,0 +1,23 @@
+package ginlogger
+
+import (
+	"testing"
+)
+
+func TestWithKubernetesMetadata(t *testing.T) {
+	// The metadata is read once per process, this is its only reader in tests
+	t.Setenv("POD_NAME", "api-7d9f-x2x4")
+	t.Setenv("POD_NAMESPACE", "shop")
+	t.Setenv("NODE_NAME", "")
+
+	base, buf := newTestLogger()
+	WithKubernetesMetadata(base).Info("Started")
+
+	entry := buf.entries(t)[0]
+	if entry["pod_name"] != "api-7d9f-x2x4" || entry["namespace"] != "shop" {
+		t.Errorf("entry = %v, want pod_name and namespace", entry)
+	}
+	if _, ok := entry["node_name"]; ok {
+		t.Errorf("unset NODE_NAME logged: %v", entry)
+	}
+}
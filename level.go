@@ -0,0 +1,151 @@
+package ginlogger
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// maxQueuedWrites bounds the number of entries waiting for the log writer
+const maxQueuedWrites = 1024
+
+// States of a queued entry
+const (
+	entryQueued int32 = iota
+	entryWriting
+	entryDropped
+)
+
+// queuedEntry is an entry handed to the log writer goroutine
+type queuedEntry struct {
+	logger Logger
+	level  string
+	msg    string
+	fields []zap.Field
+	state  atomic.Int32
+	done   chan struct{}
+}
+
+// droppedEntries counts the entries dropped by all write queues
+var droppedEntries atomic.Uint64
+
+// DroppedLogEntries returns the number of entries dropped because the log sink
+// did not accept them within LogWriteTimeout
+func DroppedLogEntries() uint64 {
+	return droppedEntries.Load()
+}
+
+// loggerOrGlobal returns logger, or the global logger when it is nil
+func loggerOrGlobal(logger Logger) Logger {
+	if logger == nil {
+		return GetLogger()
+	}
+	return logger
+}
+
+// statusLevel returns the log level and message for a response status
+func statusLevel(status int) (string, string) {
+	switch {
+	case status >= 500:
+		return LevelError, "Server error"
+	case status >= 400:
+		return LevelWarn, "Client error"
+	case status >= 300:
+		return LevelInfo, "Redirection"
+	default:
+		return LevelInfo, "Request completed"
+	}
+}
+
+// logAtLevel logs msg at the given level. Middleware never exits or panics,
+// so fatal and panic levels are logged as errors and unknown levels as info.
+// Entries below the LOG_LEVEL override are dropped unless BoostLogging
+// enables them.
+func logAtLevel(logger Logger, level string, msg string, fields ...zap.Field) {
+	if parsed, err := zapcore.ParseLevel(level); err == nil && !levelAllowed(parsed) {
+		return
+	}
+
+	switch level {
+	case LevelDebug:
+		logger.Debug(msg, fields...)
+	case LevelWarn:
+		logger.Warn(msg, fields...)
+	case LevelError, LevelFatal, LevelPanic:
+		logger.Error(msg, fields...)
+	default:
+		logger.Info(msg, fields...)
+	}
+}
+
+// writeQueue hands the entries of one logger to a single writer goroutine,
+// so that a blocked sink holds up one goroutine rather than one per request,
+// and only delays the entries of its own logger. The zero value is ready to
+// use; the goroutine starts with the first entry.
+type writeQueue struct {
+	once    sync.Once
+	entries chan *queuedEntry
+}
+
+// log logs like logAtLevel but stops waiting for a blocked sink after
+// timeout. An entry that is still queued on timeout is dropped and counted;
+// one the writer has already started on is written but no longer waited for.
+// Fields are snapshotted first, as the caller may reuse what they reference
+// once it stops waiting. A zero timeout logs synchronously.
+func (q *writeQueue) log(timeout time.Duration, logger Logger, level string, msg string, fields ...zap.Field) {
+	if timeout <= 0 {
+		logAtLevel(logger, level, msg, fields...)
+		return
+	}
+
+	q.once.Do(func() {
+		q.entries = make(chan *queuedEntry, maxQueuedWrites)
+		go q.run()
+	})
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	entry := &queuedEntry{logger: logger, level: level, msg: msg, fields: snapshotFields(fields), done: make(chan struct{})}
+	select {
+	case q.entries <- entry:
+	case <-timer.C:
+		droppedEntries.Add(1)
+		return
+	}
+
+	select {
+	case <-entry.done:
+	case <-timer.C:
+		if entry.state.CompareAndSwap(entryQueued, entryDropped) {
+			droppedEntries.Add(1)
+		}
+	}
+}
+
+// run writes queued entries until the process exits, skipping those
+// dropped while waiting
+func (q *writeQueue) run() {
+	for entry := range q.entries {
+		if entry.state.CompareAndSwap(entryQueued, entryWriting) {
+			logAtLevel(entry.logger, entry.level, entry.msg, entry.fields...)
+		}
+		close(entry.done)
+	}
+}
+
+// levelAllowed reports whether BoostLogging and the runtime level override
+// let entries at level through. Without either, the logger's own level decides.
+func levelAllowed(level zapcore.Level) bool {
+	if boosted, ok := BoostedLevel(); ok && level >= boosted {
+		return true
+	}
+
+	if override, ok := overrideLevel(); ok {
+		return level >= override
+	}
+	return true
+}
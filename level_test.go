@@ -0,0 +1,118 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPathLevelOverrides(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger: logger,
+		PathLevelOverrides: []PathLevelRule{
+			{Path: regexp.MustCompile(`^/public/`), Level: "debug"},
+			{Path: regexp.MustCompile(`^/public/docs`), Level: "warn"},
+		},
+	})
+
+	fail := func(c *gin.Context) { c.Status(http.StatusInternalServerError) }
+	serve(httptest.NewRequest(http.MethodGet, "/public/docs", nil), "/public/docs", ok, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/public/fail", nil), "/public/fail", fail, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/users", nil), "/users", ok, middleware)
+
+	want := []string{"debug", "error", "info"}
+	entries := buf.entries(t)
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if entry["level"] != want[i] {
+			t.Errorf("%v logged at %v, want %s", entry["path"], entry["level"], want[i])
+		}
+	}
+}
+
+// blockingWriter blocks every write until release is closed
+type blockingWriter struct {
+	syncBuffer
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	select {
+	case w.started <- struct{}{}:
+	default:
+	}
+	<-w.release
+	return w.syncBuffer.Write(p)
+}
+
+func TestLogWriteTimeoutDropsQueuedEntries(t *testing.T) {
+	writer := &blockingWriter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:          NewWriterLogger(writer, "info"),
+		LogWriteTimeout: 100 * time.Millisecond,
+	})
+	dropped := DroppedLogEntries()
+
+	// The first entry is picked up by the writer, which then blocks
+	start := time.Now()
+	serve(httptest.NewRequest(http.MethodGet, "/first", nil), "/:path", ok, middleware)
+	<-writer.started
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("request waited %v for a blocked sink", elapsed)
+	}
+
+	// The second entry is still queued when its timeout expires
+	serve(httptest.NewRequest(http.MethodGet, "/second", nil), "/:path", ok, middleware)
+	if got := DroppedLogEntries() - dropped; got != 1 {
+		t.Fatalf("DroppedLogEntries() increased by %d, want 1", got)
+	}
+
+	close(writer.release)
+	// Written once the writer passed the dropped entry
+	serve(httptest.NewRequest(http.MethodGet, "/third", nil), "/:path", ok, middleware)
+
+	output := writer.String()
+	if !strings.Contains(output, "/first") || strings.Contains(output, "/second") || !strings.Contains(output, "/third") {
+		t.Fatalf("output = %q, want the first and third entries", output)
+	}
+}
+
+func TestLogWriteTimeoutQueuePerLogger(t *testing.T) {
+	writer := &blockingWriter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(writer.release)
+	blocked := StructuredLogger(StructuredLoggerConfig{
+		Logger:          NewWriterLogger(writer, "info"),
+		LogWriteTimeout: 50 * time.Millisecond,
+	})
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, blocked)
+	<-writer.started
+
+	// A stuck sink does not hold up the entries of another logger
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogWriteTimeout: time.Second})
+	start := time.Now()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("request waited %v behind another logger's sink", elapsed)
+	}
+	findEntry(t, buf.entries(t), "Request completed")
+}
+
+func TestLogWriteTimeoutWaitsForFastSink(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogWriteTimeout: time.Second})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+
+	// The entry is written before the request returns
+	findEntry(t, buf.entries(t), "Request completed")
+}
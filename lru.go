@@ -0,0 +1,55 @@
+package ginlogger
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// seenCache is a bounded LRU of recently seen keys
+type seenCache struct {
+	mu       sync.Mutex
+	capacity int
+	window   time.Duration
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type seenEntry struct {
+	key  string
+	seen time.Time
+}
+
+func newSeenCache(capacity int, window time.Duration) *seenCache {
+	return &seenCache{
+		capacity: capacity,
+		window:   window,
+		entries:  make(map[string]*list.Element, capacity),
+		order:    list.New(),
+	}
+}
+
+// Seen records key and reports whether it was already seen within the window
+func (s *seenCache) Seen(key string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*seenEntry)
+		duplicate := now.Sub(entry.seen) <= s.window
+		entry.seen = now
+		s.order.MoveToFront(elem)
+		return duplicate
+	}
+
+	s.entries[key] = s.order.PushFront(&seenEntry{key: key, seen: now})
+
+	// Evict the least recently seen key when over capacity
+	if s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*seenEntry).key)
+	}
+
+	return false
+}
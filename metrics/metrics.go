@@ -0,0 +1,114 @@
+// Package metrics provides Prometheus request metrics middleware that complements
+// the gin-logger access logs. The Prometheus client is only required by this
+// module, not by gin-logger itself.
+package metrics
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Config defines the config for the metrics middleware
+type Config struct {
+	// Registerer defaults to prometheus.DefaultRegisterer
+	Registerer prometheus.Registerer
+	Namespace  string
+	// Buckets defaults to prometheus.DefBuckets
+	Buckets []float64
+	// ExemplarFunc returns exemplar labels (e.g. a trace_id) attached to the
+	// request duration observation. Returning an empty map records no exemplar.
+	ExemplarFunc func(*gin.Context) map[string]string
+	// ExtraLabels are additional labels of the histogram that handlers set
+	// per request with AddMetricLabel (e.g. "cache"). Labels not listed here
+	// are ignored, which bounds cardinality; unset labels are empty.
+	ExtraLabels []string
+}
+
+// labelsKey is the gin context key of the labels added via AddMetricLabel
+const labelsKey = "ginlogger.metrics.labels"
+
+// AddMetricLabel sets a label (e.g. "cache" to "hit") on the current
+// request's observation. key must be listed in Config.ExtraLabels; value
+// should come from a small fixed set to keep the metric's cardinality low.
+func AddMetricLabel(c *gin.Context, key, value string) {
+	existing, _ := c.Get(labelsKey)
+	labels, _ := existing.(map[string]string)
+	if labels == nil {
+		labels = make(map[string]string)
+		c.Set(labelsKey, labels)
+	}
+	labels[key] = value
+}
+
+// Middleware returns a gin.HandlerFunc that records the http_request_duration_seconds
+// histogram partitioned by method, route and status
+func Middleware(config Config) gin.HandlerFunc {
+	if config.Registerer == nil {
+		config.Registerer = prometheus.DefaultRegisterer
+	}
+
+	if len(config.Buckets) == 0 {
+		config.Buckets = prometheus.DefBuckets
+	}
+
+	duration := registerHistogramVec(config.Registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: config.Namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Duration of HTTP requests in seconds.",
+		Buckets:   config.Buckets,
+	}, append([]string{"method", "route", "status"}, config.ExtraLabels...)))
+
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		labelValues := []string{c.Request.Method, route, strconv.Itoa(c.Writer.Status())}
+		if len(config.ExtraLabels) > 0 {
+			value, _ := c.Get(labelsKey)
+			labels, _ := value.(map[string]string)
+			for _, name := range config.ExtraLabels {
+				labelValues = append(labelValues, labels[name])
+			}
+		}
+
+		observer := duration.WithLabelValues(labelValues...)
+		seconds := time.Since(start).Seconds()
+
+		// Attach an exemplar so a latency spike can be traced back to a request
+		if config.ExemplarFunc != nil {
+			if labels := config.ExemplarFunc(c); len(labels) > 0 {
+				if exemplarObserver, ok := observer.(prometheus.ExemplarObserver); ok {
+					exemplarObserver.ObserveWithExemplar(seconds, labels)
+					return
+				}
+			}
+		}
+
+		observer.Observe(seconds)
+	}
+}
+
+// registerHistogramVec registers the collector, reusing an already registered
+// one so that the middleware can be constructed more than once
+func registerHistogramVec(registerer prometheus.Registerer, collector *prometheus.HistogramVec) *prometheus.HistogramVec {
+	if err := registerer.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			if existing, ok := alreadyRegistered.ExistingCollector.(*prometheus.HistogramVec); ok {
+				return existing
+			}
+		}
+		panic(err)
+	}
+	return collector
+}
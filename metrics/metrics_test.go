@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// observe serves one request per path through Middleware(config) and returns
+// the gathered duration histograms
+func observe(t *testing.T, config Config, handler gin.HandlerFunc, paths ...string) []*dto.Metric {
+	t.Helper()
+
+	registry := prometheus.NewRegistry()
+	config.Registerer = registry
+
+	r := gin.New()
+	r.Use(Middleware(config))
+	r.GET("/items/:id", handler)
+	for _, path := range paths {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() == "http_request_duration_seconds" {
+			return family.GetMetric()
+		}
+	}
+	t.Fatal("http_request_duration_seconds not registered")
+	return nil
+}
+
+// labels returns the label pairs of metric as a map
+func labels(metric *dto.Metric) map[string]string {
+	pairs := make(map[string]string)
+	for _, pair := range metric.GetLabel() {
+		pairs[pair.GetName()] = pair.GetValue()
+	}
+	return pairs
+}
+
+func TestMiddlewareExemplar(t *testing.T) {
+	config := Config{
+		ExemplarFunc: func(c *gin.Context) map[string]string {
+			return map[string]string{"trace_id": "0102030405060708090a0b0c0d0e0f10"}
+		},
+	}
+	metrics := observe(t, config, func(c *gin.Context) { c.Status(http.StatusOK) }, "/items/1")
+
+	if len(metrics) != 1 {
+		t.Fatalf("metrics = %v, want one series", metrics)
+	}
+	if got := labels(metrics[0]); got["route"] != "/items/:id" || got["status"] != "200" || got["method"] != "GET" {
+		t.Fatalf("labels = %v, want the route pattern and status", got)
+	}
+
+	var exemplar *dto.Exemplar
+	for _, bucket := range metrics[0].GetHistogram().GetBucket() {
+		if bucket.GetExemplar() != nil {
+			exemplar = bucket.GetExemplar()
+		}
+	}
+	if exemplar == nil || exemplar.GetLabel()[0].GetValue() != "0102030405060708090a0b0c0d0e0f10" {
+		t.Fatalf("exemplar = %v, want the trace_id", exemplar)
+	}
+}
+
+func TestMiddlewareWithoutExemplar(t *testing.T) {
+	config := Config{ExemplarFunc: func(c *gin.Context) map[string]string { return nil }}
+	metrics := observe(t, config, func(c *gin.Context) { c.Status(http.StatusOK) }, "/items/1")
+
+	for _, bucket := range metrics[0].GetHistogram().GetBucket() {
+		if bucket.GetExemplar() != nil {
+			t.Fatalf("exemplar %v recorded for an unsampled request", bucket.GetExemplar())
+		}
+	}
+	if metrics[0].GetHistogram().GetSampleCount() != 1 {
+		t.Fatalf("sample count = %d, want 1", metrics[0].GetHistogram().GetSampleCount())
+	}
+}
+
+func TestAddMetricLabel(t *testing.T) {
+	handler := func(c *gin.Context) {
+		if c.Param("id") == "hit" {
+			AddMetricLabel(c, "cache", "hit")
+			AddMetricLabel(c, "tenant", "acme")
+		}
+		c.Status(http.StatusOK)
+	}
+	metrics := observe(t, Config{ExtraLabels: []string{"cache"}}, handler, "/items/hit", "/items/plain")
+
+	if len(metrics) != 2 {
+		t.Fatalf("metrics = %v, want one series per cache value", metrics)
+	}
+	seen := make(map[string]bool)
+	for _, metric := range metrics {
+		got := labels(metric)
+		if _, ok := got["tenant"]; ok {
+			t.Fatalf("labels = %v, want labels outside ExtraLabels ignored", got)
+		}
+		seen[got["cache"]] = true
+	}
+	if !seen["hit"] || !seen[""] {
+		t.Fatalf("cache labels = %v, want \"hit\" and an empty value for the unset label", seen)
+	}
+}
@@ -0,0 +1,97 @@
+// Package opentracing provides OpenTracing integration for the gin-logger
+// middleware, for stacks that have not migrated to OpenTelemetry yet.
+package opentracing
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/gin-gonic/gin"
+	ot "github.com/opentracing/opentracing-go"
+	"go.uber.org/zap"
+)
+
+// IDExtractor returns the trace and span IDs of a tracer-specific span context
+type IDExtractor func(spanContext ot.SpanContext) (traceID, spanID string, ok bool)
+
+// TraceFields returns trace_id and span_id for the OpenTracing span active in
+// the request context. It is intended to be used as
+// StructuredLoggerConfig.CustomFields and understands span contexts exposing
+// TraceID and SpanID methods (e.g. Jaeger).
+func TraceFields(c *gin.Context) []zap.Field {
+	return TraceFieldsWithExtractor(DefaultIDExtractor)(c)
+}
+
+// TraceFieldsWithExtractor returns a CustomFields function using extract for
+// tracers whose span contexts DefaultIDExtractor does not understand
+func TraceFieldsWithExtractor(extract IDExtractor) func(c *gin.Context) []zap.Field {
+	return func(c *gin.Context) []zap.Field {
+		span := ot.SpanFromContext(c.Request.Context())
+		if span == nil {
+			return nil
+		}
+
+		traceID, spanID, ok := extract(span.Context())
+		if !ok {
+			return nil
+		}
+
+		return []zap.Field{
+			zap.String("trace_id", traceID),
+			zap.String("span_id", spanID),
+		}
+	}
+}
+
+// TraceID returns the trace ID of the OpenTracing span active in ctx, read
+// with DefaultIDExtractor, or "" without one. Pass it to
+// ginlogger.SetTraceIDExtractor so that DetachedLogger carries the trace_id.
+func TraceID(ctx context.Context) string {
+	span := ot.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+
+	traceID, _, _ := DefaultIDExtractor(span.Context())
+	return traceID
+}
+
+// DefaultIDExtractor reads IDs from span contexts with TraceID and SpanID
+// methods returning a string or a fmt.Stringer (e.g. Jaeger's IDs). Other
+// tracers need their own IDExtractor.
+func DefaultIDExtractor(spanContext ot.SpanContext) (string, string, bool) {
+	if ids, ok := spanContext.(interface {
+		TraceID() string
+		SpanID() string
+	}); ok {
+		return ids.TraceID(), ids.SpanID(), true
+	}
+
+	traceID, ok := stringerMethod(spanContext, "TraceID")
+	if !ok {
+		return "", "", false
+	}
+
+	spanID, ok := stringerMethod(spanContext, "SpanID")
+	if !ok {
+		return "", "", false
+	}
+
+	return traceID, spanID, true
+}
+
+// stringerMethod calls the named no-argument method of value when it returns
+// a single fmt.Stringer
+func stringerMethod(value any, name string) (string, bool) {
+	method := reflect.ValueOf(value).MethodByName(name)
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return "", false
+	}
+
+	stringer, ok := method.Call(nil)[0].Interface().(fmt.Stringer)
+	if !ok {
+		return "", false
+	}
+	return stringer.String(), true
+}
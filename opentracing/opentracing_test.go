@@ -0,0 +1,124 @@
+package opentracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	ot "github.com/opentracing/opentracing-go"
+	"github.com/opentracing/opentracing-go/mocktracer"
+	"go.uber.org/zap"
+)
+
+// mockIDs extracts the IDs of mocktracer span contexts
+func mockIDs(spanContext ot.SpanContext) (string, string, bool) {
+	mock, ok := spanContext.(mocktracer.MockSpanContext)
+	if !ok {
+		return "", "", false
+	}
+	return strconv.Itoa(mock.TraceID), strconv.Itoa(mock.SpanID), true
+}
+
+type hexID uint64
+
+func (id hexID) String() string {
+	return strconv.FormatUint(uint64(id), 16)
+}
+
+// stringerSpanContext mimics Jaeger's span context
+type stringerSpanContext struct{}
+
+func (stringerSpanContext) ForeachBaggageItem(func(k, v string) bool) {}
+func (stringerSpanContext) TraceID() hexID                            { return 0xabc }
+func (stringerSpanContext) SpanID() hexID                             { return 0xdef }
+
+// stringSpanContext exposes IDs as plain strings
+type stringSpanContext struct{}
+
+func (stringSpanContext) ForeachBaggageItem(func(k, v string) bool) {}
+func (stringSpanContext) TraceID() string                           { return "trace-1" }
+func (stringSpanContext) SpanID() string                            { return "span-1" }
+
+func fieldsForRequest(t *testing.T, span ot.Span, fieldsFunc func(*gin.Context) []zap.Field) map[string]string {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	if span != nil {
+		c.Request = c.Request.WithContext(ot.ContextWithSpan(c.Request.Context(), span))
+	}
+
+	fields := make(map[string]string)
+	for _, field := range fieldsFunc(c) {
+		fields[field.Key] = field.String
+	}
+	return fields
+}
+
+func TestTraceFieldsWithExtractor(t *testing.T) {
+	tracer := mocktracer.New()
+	span := tracer.StartSpan("request").(*mocktracer.MockSpan)
+	defer span.Finish()
+
+	fields := fieldsForRequest(t, span, TraceFieldsWithExtractor(mockIDs))
+	if fields["trace_id"] != strconv.Itoa(span.SpanContext.TraceID) || fields["span_id"] != strconv.Itoa(span.SpanContext.SpanID) {
+		t.Fatalf("fields = %v, want the mock span's IDs", fields)
+	}
+}
+
+func TestDefaultIDExtractor(t *testing.T) {
+	tests := []struct {
+		name        string
+		spanContext ot.SpanContext
+		traceID     string
+		spanID      string
+		ok          bool
+	}{
+		{"stringer IDs", stringerSpanContext{}, "abc", "def", true},
+		{"string IDs", stringSpanContext{}, "trace-1", "span-1", true},
+		{"unknown span context", mocktracer.MockSpanContext{TraceID: 1, SpanID: 2}, "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traceID, spanID, ok := DefaultIDExtractor(tt.spanContext)
+			if traceID != tt.traceID || spanID != tt.spanID || ok != tt.ok {
+				t.Fatalf("DefaultIDExtractor() = %q, %q, %v, want %q, %q, %v", traceID, spanID, ok, tt.traceID, tt.spanID, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTraceFieldsWithoutSpan(t *testing.T) {
+	if fields := fieldsForRequest(t, nil, TraceFields); len(fields) != 0 {
+		t.Fatalf("fields = %v, want none without an active span", fields)
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	ctx := ot.ContextWithSpan(context.Background(), &stringSpan{})
+	if got := TraceID(ctx); got != "trace-1" {
+		t.Errorf("TraceID() = %q, want trace-1", got)
+	}
+
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() = %q without a span, want empty", got)
+	}
+}
+
+// stringSpan is a span with a stringSpanContext
+type stringSpan struct {
+	ot.Span
+}
+
+func (*stringSpan) Context() ot.SpanContext {
+	return stringSpanContext{}
+}
+
+func (*stringSpan) Tracer() ot.Tracer {
+	return ot.NoopTracer{}
+}
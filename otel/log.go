@@ -0,0 +1,150 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	ginlogger "github.com/csmart-libs/gin-logger"
+	otellog "go.opentelemetry.io/otel/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// instrumentationName identifies log records emitted by the bridge
+const instrumentationName = "github.com/csmart-libs/gin-logger"
+
+// LogBridge is a ginlogger.Logger that emits every entry as an OpenTelemetry
+// log record, so access logs travel through the same OTLP pipeline as traces
+// and metrics. Fields become record attributes.
+type LogBridge struct {
+	logger otellog.Logger
+	attrs  []otellog.KeyValue
+}
+
+// NewLogBridge returns a LogBridge emitting to a logger of the given provider
+// (e.g. an sdk/log LoggerProvider with an OTLP exporter). Use it as the Logger
+// of a middleware config, or combine it with the regular output via ginlogger.TeeLogger.
+// Entries of StructuredLogger are emitted in the request context, so records
+// carry the trace and span IDs of the request.
+func NewLogBridge(provider otellog.LoggerProvider) *LogBridge {
+	return &LogBridge{logger: provider.Logger(instrumentationName)}
+}
+
+func (b *LogBridge) Debug(msg string, fields ...zap.Field) {
+	b.emit(otellog.SeverityDebug, "DEBUG", msg, fields)
+}
+
+func (b *LogBridge) Info(msg string, fields ...zap.Field) {
+	b.emit(otellog.SeverityInfo, "INFO", msg, fields)
+}
+
+func (b *LogBridge) Warn(msg string, fields ...zap.Field) {
+	b.emit(otellog.SeverityWarn, "WARN", msg, fields)
+}
+
+func (b *LogBridge) Error(msg string, fields ...zap.Field) {
+	b.emit(otellog.SeverityError, "ERROR", msg, fields)
+}
+
+func (b *LogBridge) Fatal(msg string, fields ...zap.Field) {
+	b.emit(otellog.SeverityFatal, "FATAL", msg, fields)
+	os.Exit(1)
+}
+
+func (b *LogBridge) Panic(msg string, fields ...zap.Field) {
+	b.emit(otellog.SeverityFatal, "PANIC", msg, fields)
+	panic(msg)
+}
+
+func (b *LogBridge) With(fields ...zap.Field) ginlogger.Logger {
+	attrs := make([]otellog.KeyValue, 0, len(b.attrs)+len(fields))
+	attrs = append(attrs, b.attrs...)
+	attrs = append(attrs, fieldsToKeyValues(fields)...)
+	return &LogBridge{logger: b.logger, attrs: attrs}
+}
+
+// Sync is a no-op, flushing is handled by the LoggerProvider
+func (b *LogBridge) Sync() error {
+	return nil
+}
+
+// emit emits a record in the request context carried by the fields (see
+// ginlogger.ContextFromFields), so that the SDK correlates it with the
+// request's span
+func (b *LogBridge) emit(severity otellog.Severity, severityText, msg string, fields []zap.Field) {
+	ctx := ginlogger.ContextFromFields(fields)
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if !b.logger.Enabled(ctx, otellog.EnabledParameters{Severity: severity}) {
+		return
+	}
+
+	var record otellog.Record
+	record.SetTimestamp(time.Now())
+	record.SetSeverity(severity)
+	record.SetSeverityText(severityText)
+	record.SetBody(otellog.StringValue(msg))
+	record.AddAttributes(b.attrs...)
+	record.AddAttributes(fieldsToKeyValues(fields)...)
+
+	b.logger.Emit(ctx, record)
+}
+
+// fieldsToKeyValues converts zap fields to log attributes, preserving their order
+func fieldsToKeyValues(fields []zap.Field) []otellog.KeyValue {
+	attrs := make([]otellog.KeyValue, 0, len(fields))
+	for _, field := range fields {
+		encoder := zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+		for key, value := range encoder.Fields {
+			attrs = append(attrs, otellog.KeyValue{Key: key, Value: toValue(value)})
+		}
+	}
+	return attrs
+}
+
+func toValue(value any) otellog.Value {
+	switch v := value.(type) {
+	case string:
+		return otellog.StringValue(v)
+	case bool:
+		return otellog.BoolValue(v)
+	case int:
+		return otellog.IntValue(v)
+	case int64:
+		return otellog.Int64Value(v)
+	case int32:
+		return otellog.Int64Value(int64(v))
+	case uint32:
+		return otellog.Int64Value(int64(v))
+	case float64:
+		return otellog.Float64Value(v)
+	case float32:
+		return otellog.Float64Value(float64(v))
+	case []byte:
+		return otellog.BytesValue(v)
+	case time.Duration:
+		return otellog.StringValue(v.String())
+	case time.Time:
+		return otellog.StringValue(v.Format(time.RFC3339Nano))
+	case []any:
+		values := make([]otellog.Value, len(v))
+		for i, item := range v {
+			values[i] = toValue(item)
+		}
+		return otellog.SliceValue(values...)
+	case map[string]any:
+		kvs := make([]otellog.KeyValue, 0, len(v))
+		for key, item := range v {
+			kvs = append(kvs, otellog.KeyValue{Key: key, Value: toValue(item)})
+		}
+		return otellog.MapValue(kvs...)
+	case fmt.Stringer:
+		return otellog.StringValue(v.String())
+	default:
+		return otellog.StringValue(fmt.Sprint(v))
+	}
+}
@@ -0,0 +1,93 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	ginlogger "github.com/csmart-libs/gin-logger"
+	"github.com/gin-gonic/gin"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// memoryExporter keeps the records exported by an sdk/log processor
+type memoryExporter struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (e *memoryExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, record := range records {
+		e.records = append(e.records, record.Clone())
+	}
+	return nil
+}
+
+func (e *memoryExporter) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (e *memoryExporter) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// attributes returns the attributes of record as a map
+func attributes(record sdklog.Record) map[string]otellog.Value {
+	attrs := make(map[string]otellog.Value)
+	record.WalkAttributes(func(kv otellog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestLogBridge(t *testing.T) {
+	exporter := &memoryExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+	bridge := NewLogBridge(provider)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	// A tracing middleware starting the request span
+	r.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), spanContext(true)))
+	})
+	r.Use(ginlogger.StructuredLogger(ginlogger.StructuredLoggerConfig{Logger: bridge.With(zap.String("service", "api"))}))
+	r.GET("/missing", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	if len(exporter.records) != 1 {
+		t.Fatalf("exported %d records, want the access log entry", len(exporter.records))
+	}
+
+	record := exporter.records[0]
+	if record.Body().AsString() != "Client error" || record.Severity() != otellog.SeverityWarn || record.SeverityText() != "WARN" {
+		t.Fatalf("record = %q at %v (%s), want the warning", record.Body().AsString(), record.Severity(), record.SeverityText())
+	}
+	if record.InstrumentationScope().Name != instrumentationName {
+		t.Errorf("scope = %q, want %q", record.InstrumentationScope().Name, instrumentationName)
+	}
+	if record.Timestamp().IsZero() {
+		t.Error("record has no timestamp")
+	}
+	if record.TraceID() != spanContext(true).TraceID() || record.SpanID() != spanContext(true).SpanID() {
+		t.Errorf("record trace = %s/%s, want the request span", record.TraceID(), record.SpanID())
+	}
+
+	attrs := attributes(record)
+	if attrs["service"].AsString() != "api" || attrs["status"].AsInt64() != http.StatusNotFound || attrs["method"].AsString() != http.MethodGet {
+		t.Fatalf("attributes = %v, want the converted fields", attrs)
+	}
+	if attrs["latency"].Kind() != otellog.KindString {
+		t.Errorf("latency = %v, want a duration string", attrs["latency"])
+	}
+}
@@ -0,0 +1,74 @@
+// Package otel provides OpenTelemetry integration for the gin-logger middleware:
+// trace correlation fields, span events and a log record bridge. It is a
+// separate Go module, which keeps the OpenTelemetry API out of the
+// dependencies of the core module.
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// TraceFields returns trace correlation fields for the span active in the
+// request context. It is intended to be used as StructuredLoggerConfig.CustomFields.
+//
+// The trace_sampled field reports whether the trace was sampled, which tells
+// log readers whether looking up the trace_id will actually find a trace.
+func TraceFields(c *gin.Context) []zap.Field {
+	spanContext := trace.SpanContextFromContext(c.Request.Context())
+	if !spanContext.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String("trace_id", spanContext.TraceID().String()),
+		zap.String("span_id", spanContext.SpanID().String()),
+		zap.Bool("trace_sampled", spanContext.IsSampled()),
+	}
+}
+
+// TraceID returns the trace ID of the span active in ctx, or "" without one.
+// Pass it to ginlogger.SetTraceIDExtractor so that DetachedLogger carries the
+// trace_id.
+func TraceID(ctx context.Context) string {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.TraceID().IsValid() {
+		return ""
+	}
+	return spanContext.TraceID().String()
+}
+
+// ExemplarLabels returns the trace_id of the sampled span active in the request
+// context as exemplar labels. It is intended to be used as metrics.Config.ExemplarFunc
+// so operators can jump from a latency spike straight to the trace.
+func ExemplarLabels(c *gin.Context) map[string]string {
+	spanContext := trace.SpanContextFromContext(c.Request.Context())
+	if !spanContext.IsValid() || !spanContext.IsSampled() {
+		return nil
+	}
+
+	return map[string]string{"trace_id": spanContext.TraceID().String()}
+}
+
+// SlowRequestSpanEvent marks the span active in the request context as slow,
+// setting slow_request and latency_ms attributes and adding a "slow_request"
+// event, so the slowness is visible in the trace. It is intended to be used as
+// PerformanceLoggerConfig.OnSlowRequest.
+func SlowRequestSpanEvent(c *gin.Context, latency time.Duration) {
+	span := trace.SpanFromContext(c.Request.Context())
+	if !span.IsRecording() {
+		return
+	}
+
+	attributes := []attribute.KeyValue{
+		attribute.Bool("slow_request", true),
+		attribute.Int64("latency_ms", latency.Milliseconds()),
+	}
+	span.SetAttributes(attributes...)
+	span.AddEvent("slow_request", trace.WithAttributes(attributes...))
+}
@@ -0,0 +1,136 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// testContext returns a gin context for a request carrying spanContext
+func testContext(spanContext trace.SpanContext) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request = c.Request.WithContext(trace.ContextWithSpanContext(c.Request.Context(), spanContext))
+	return c
+}
+
+// spanContext returns a remote span context with fixed IDs
+func spanContext(sampled bool) trace.SpanContext {
+	config := trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f, 0x10},
+		SpanID:  trace.SpanID{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08},
+		Remote:  true,
+	}
+	if sampled {
+		config.TraceFlags = trace.FlagsSampled
+	}
+	return trace.NewSpanContext(config)
+}
+
+func TestTraceFields(t *testing.T) {
+	tests := []struct {
+		name        string
+		spanContext trace.SpanContext
+		sampled     any
+	}{
+		{"sampled", spanContext(true), true},
+		{"not sampled", spanContext(false), false},
+		{"no span", trace.SpanContext{}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := make(map[string]any)
+			for _, field := range TraceFields(testContext(tt.spanContext)) {
+				if field.Key == "trace_sampled" {
+					fields[field.Key] = field.Integer == 1
+				} else {
+					fields[field.Key] = field.String
+				}
+			}
+
+			if fields["trace_sampled"] != tt.sampled {
+				t.Fatalf("trace_sampled = %v, want %v", fields["trace_sampled"], tt.sampled)
+			}
+			if tt.sampled != nil && fields["trace_id"] != "0102030405060708090a0b0c0d0e0f10" {
+				t.Fatalf("trace_id = %v, want the span's trace ID", fields["trace_id"])
+			}
+		})
+	}
+}
+
+func TestExemplarLabels(t *testing.T) {
+	labels := ExemplarLabels(testContext(spanContext(true)))
+	if labels["trace_id"] != "0102030405060708090a0b0c0d0e0f10" {
+		t.Fatalf("ExemplarLabels() = %v, want the trace_id", labels)
+	}
+
+	if labels := ExemplarLabels(testContext(spanContext(false))); labels != nil {
+		t.Fatalf("ExemplarLabels() = %v for an unsampled span, want none", labels)
+	}
+}
+
+// recordingSpan records the attributes and events set on it
+type recordingSpan struct {
+	noop.Span
+	attributes []attribute.KeyValue
+	events     []string
+}
+
+func (s *recordingSpan) IsRecording() bool { return true }
+
+func (s *recordingSpan) SetAttributes(attributes ...attribute.KeyValue) {
+	s.attributes = append(s.attributes, attributes...)
+}
+
+func (s *recordingSpan) AddEvent(name string, options ...trace.EventOption) {
+	s.events = append(s.events, name)
+}
+
+func TestSlowRequestSpanEvent(t *testing.T) {
+	span := &recordingSpan{}
+	c := testContext(spanContext(true))
+	c.Request = c.Request.WithContext(trace.ContextWithSpan(c.Request.Context(), span))
+
+	SlowRequestSpanEvent(c, 1500*time.Millisecond)
+
+	want := map[attribute.Key]attribute.Value{
+		"slow_request": attribute.BoolValue(true),
+		"latency_ms":   attribute.Int64Value(1500),
+	}
+	if len(span.attributes) != len(want) {
+		t.Fatalf("attributes = %v, want %v", span.attributes, want)
+	}
+	for _, attr := range span.attributes {
+		if want[attr.Key] != attr.Value {
+			t.Errorf("attribute %s = %v, want %v", attr.Key, attr.Value.Emit(), want[attr.Key].Emit())
+		}
+	}
+	if len(span.events) != 1 || span.events[0] != "slow_request" {
+		t.Errorf("events = %v, want slow_request", span.events)
+	}
+}
+
+func TestSlowRequestSpanEventWithoutRecordingSpan(t *testing.T) {
+	// A remote span context is not recording, nothing is set and nothing panics
+	SlowRequestSpanEvent(testContext(spanContext(true)), time.Second)
+}
+
+func TestTraceID(t *testing.T) {
+	ctx := trace.ContextWithSpanContext(context.Background(), spanContext(true))
+	if got := TraceID(ctx); got != "0102030405060708090a0b0c0d0e0f10" {
+		t.Errorf("TraceID() = %q, want the span's trace ID", got)
+	}
+
+	if got := TraceID(context.Background()); got != "" {
+		t.Errorf("TraceID() = %q without a span, want empty", got)
+	}
+}
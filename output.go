@@ -0,0 +1,232 @@
+package ginlogger
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// zapLogger adapts a *zap.Logger to the Logger interface
+type zapLogger struct {
+	logger *zap.Logger
+}
+
+// NewZapLogger wraps an existing *zap.Logger as a Logger
+func NewZapLogger(logger *zap.Logger) Logger {
+	// Skip the adapter frame so callers are reported correctly
+	return &zapLogger{logger: logger.WithOptions(zap.AddCallerSkip(1))}
+}
+
+// NewWriterLogger returns a Logger that encodes entries as JSON lines to w.
+// Entries below level ("debug", "info", ...) are discarded; an invalid level
+// defaults to info.
+func NewWriterLogger(w io.Writer, level string) Logger {
+	return NewZapLogger(zap.New(newWriterCore(zapcore.AddSync(w), boostEnabler{parseLevel(level)}), zap.AddCaller()))
+}
+
+// BufferOptions configures buffered log output
+type BufferOptions struct {
+	// Size is the buffer size in bytes; entries are written once it is full (default 256kB)
+	Size int
+	// FlushInterval is the maximum time an entry stays buffered (default 30s)
+	FlushInterval time.Duration
+}
+
+// NewBufferedWriterLogger is like NewWriterLogger but buffers entries in
+// memory and writes them to w in larger chunks, trading durability for fewer
+// writes. Flush (or FlushHandler) writes the buffered entries immediately.
+func NewBufferedWriterLogger(w io.Writer, level string, options BufferOptions) Logger {
+	ws := &zapcore.BufferedWriteSyncer{
+		WS:            zapcore.AddSync(w),
+		Size:          options.Size,
+		FlushInterval: options.FlushInterval,
+	}
+
+	logger := NewZapLogger(zap.New(newWriterCore(ws, boostEnabler{parseLevel(level)}), zap.AddCaller()))
+	registerFlush(logger)
+	registerOutput(func() { ws.Stop() })
+	return logger
+}
+
+// LevelRoutingConfig defines the config for WithLevelRoutingConfig
+type LevelRoutingConfig struct {
+	// AccessWriter receives entries below SplitLevel
+	AccessWriter io.Writer
+	// ErrorWriter receives entries at or above SplitLevel
+	ErrorWriter io.Writer
+	// Level is the minimum level written at all ("debug", "info", ...),
+	// lowered by BoostLogging (default info)
+	Level string
+	// SplitLevel is the lowest level routed to ErrorWriter (default warn)
+	SplitLevel string
+}
+
+// WithLevelRouting returns a Logger that writes info entries to accessWriter
+// and entries at or above splitLevel to errorWriter, e.g. to keep warnings and
+// errors in a separate file for alerting:
+//
+//	logger := WithLevelRouting(accessLog, errorLog, zapcore.WarnLevel)
+//
+// Use WithLevelRoutingConfig for another minimum level.
+func WithLevelRouting(accessWriter, errorWriter io.Writer, splitLevel zapcore.Level) Logger {
+	return WithLevelRoutingConfig(LevelRoutingConfig{
+		AccessWriter: accessWriter,
+		ErrorWriter:  errorWriter,
+		SplitLevel:   splitLevel.String(),
+	})
+}
+
+// WithLevelRoutingConfig returns a level routing Logger using configs
+func WithLevelRoutingConfig(config LevelRoutingConfig) Logger {
+	if config.SplitLevel == "" {
+		config.SplitLevel = LevelWarn
+	}
+
+	minimum := boostEnabler{parseLevel(config.Level)}
+	splitLevel := parseLevel(config.SplitLevel)
+
+	access := newWriterCore(zapcore.AddSync(config.AccessWriter), zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level < splitLevel && minimum.Enabled(level)
+	}))
+	errs := newWriterCore(zapcore.AddSync(config.ErrorWriter), zap.LevelEnablerFunc(func(level zapcore.Level) bool {
+		return level >= splitLevel && minimum.Enabled(level)
+	}))
+
+	return NewZapLogger(zap.New(zapcore.NewTee(access, errs), zap.AddCaller()))
+}
+
+// newWriterCore returns a JSON core using the same encoding as go-logger's production config
+func newWriterCore(ws zapcore.WriteSyncer, level zapcore.LevelEnabler) zapcore.Core {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.TimeKey = "timestamp"
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	return zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), ws, level)
+}
+
+// parseLevel parses a level string, defaulting to info
+func parseLevel(level string) zapcore.Level {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return zapcore.InfoLevel
+	}
+	return parsed
+}
+
+func (l *zapLogger) Debug(msg string, fields ...zap.Field) {
+	l.logger.Debug(msg, fields...)
+}
+
+func (l *zapLogger) Info(msg string, fields ...zap.Field) {
+	l.logger.Info(msg, fields...)
+}
+
+func (l *zapLogger) Warn(msg string, fields ...zap.Field) {
+	l.logger.Warn(msg, fields...)
+}
+
+func (l *zapLogger) Error(msg string, fields ...zap.Field) {
+	l.logger.Error(msg, fields...)
+}
+
+func (l *zapLogger) Fatal(msg string, fields ...zap.Field) {
+	l.logger.Fatal(msg, fields...)
+}
+
+func (l *zapLogger) Panic(msg string, fields ...zap.Field) {
+	l.logger.Panic(msg, fields...)
+}
+
+func (l *zapLogger) With(fields ...zap.Field) Logger {
+	return &zapLogger{logger: l.logger.With(fields...)}
+}
+
+func (l *zapLogger) Sync() error {
+	return l.logger.Sync()
+}
+
+// teeLogger duplicates entries to several loggers
+type teeLogger struct {
+	loggers []Logger
+}
+
+// TeeLogger returns a Logger that writes every entry to all given loggers.
+// Nil loggers are ignored.
+func TeeLogger(loggers ...Logger) Logger {
+	tee := &teeLogger{}
+	for _, logger := range loggers {
+		if logger != nil {
+			tee.loggers = append(tee.loggers, logger)
+		}
+	}
+	return tee
+}
+
+func (l *teeLogger) Debug(msg string, fields ...zap.Field) {
+	for _, logger := range l.loggers {
+		logger.Debug(msg, fields...)
+	}
+}
+
+func (l *teeLogger) Info(msg string, fields ...zap.Field) {
+	for _, logger := range l.loggers {
+		logger.Info(msg, fields...)
+	}
+}
+
+func (l *teeLogger) Warn(msg string, fields ...zap.Field) {
+	for _, logger := range l.loggers {
+		logger.Warn(msg, fields...)
+	}
+}
+
+func (l *teeLogger) Error(msg string, fields ...zap.Field) {
+	for _, logger := range l.loggers {
+		logger.Error(msg, fields...)
+	}
+}
+
+// Fatal logs to every logger as an error first, so that all outputs receive
+// the entry before the last logger exits the process
+func (l *teeLogger) Fatal(msg string, fields ...zap.Field) {
+	if len(l.loggers) == 0 {
+		return
+	}
+	for _, logger := range l.loggers[:len(l.loggers)-1] {
+		logger.Error(msg, fields...)
+		logger.Sync()
+	}
+	l.loggers[len(l.loggers)-1].Fatal(msg, fields...)
+}
+
+// Panic logs to every logger as an error first, then panics via the last logger
+func (l *teeLogger) Panic(msg string, fields ...zap.Field) {
+	if len(l.loggers) == 0 {
+		panic(msg)
+	}
+	for _, logger := range l.loggers[:len(l.loggers)-1] {
+		logger.Error(msg, fields...)
+	}
+	l.loggers[len(l.loggers)-1].Panic(msg, fields...)
+}
+
+func (l *teeLogger) With(fields ...zap.Field) Logger {
+	tee := &teeLogger{loggers: make([]Logger, len(l.loggers))}
+	for i, logger := range l.loggers {
+		tee.loggers[i] = logger.With(fields...)
+	}
+	return tee
+}
+
+func (l *teeLogger) Sync() error {
+	var errs []error
+	for _, logger := range l.loggers {
+		if err := logger.Sync(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
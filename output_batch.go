@@ -0,0 +1,222 @@
+package ginlogger
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errWriterClosed is returned when writing to a closed output
+var errWriterClosed = errors.New("ginlogger: writer closed")
+
+// ErrBatchRejected is wrapped by a BatchSender error when retrying cannot
+// help, e.g. the collector answered 400. The batch is dropped immediately.
+var ErrBatchRejected = errors.New("ginlogger: batch rejected")
+
+// BatchSender delivers a batch of encoded log entries. A non-nil error retries
+// the whole batch, unless it wraps ErrBatchRejected. The batch slice is reused
+// once send returns.
+type BatchSender func(batch [][]byte) error
+
+// BatchOptions configures a BatchWriter
+type BatchOptions struct {
+	// BatchSize is the number of entries per send (default 100)
+	BatchSize int
+	// FlushInterval is the maximum time an entry waits before being sent (default 5s)
+	FlushInterval time.Duration
+	// BufferSize bounds the number of queued entries (default 10000)
+	BufferSize int
+	// BlockWhenFull makes writes wait for buffer space, at most BlockTimeout
+	// (default 1s), when the destination cannot keep up. By default new entries
+	// are dropped rather than slowing down requests.
+	BlockWhenFull bool
+	BlockTimeout  time.Duration
+	// MaxRetries is the number of retries of a failed batch (default 3,
+	// negative disables retries)
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, growing linearly (default 500ms)
+	RetryBackoff time.Duration
+}
+
+// BatchWriter queues log lines and hands them in batches to a BatchSender on
+// a background goroutine, so that slow or unavailable destinations never
+// block requests. It backs the HTTP and GELF outputs and is exported for
+// outputs living in other modules, e.g. Kafka.
+type BatchWriter struct {
+	send    BatchSender
+	options BatchOptions
+	entries chan []byte
+	flush   chan chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+	dropped atomic.Uint64
+}
+
+// NewBatchWriter returns a BatchWriter delivering through send. Shutdown
+// closes it, delivering the entries still queued.
+func NewBatchWriter(send BatchSender, options BatchOptions) *BatchWriter {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 100
+	}
+
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = 5 * time.Second
+	}
+
+	if options.BufferSize <= 0 {
+		options.BufferSize = 10000
+	}
+
+	if options.BlockTimeout <= 0 {
+		options.BlockTimeout = time.Second
+	}
+
+	if options.MaxRetries < 0 {
+		options.MaxRetries = 0
+	} else if options.MaxRetries == 0 {
+		options.MaxRetries = 3
+	}
+
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = 500 * time.Millisecond
+	}
+
+	w := &BatchWriter{
+		send:    send,
+		options: options,
+		entries: make(chan []byte, options.BufferSize),
+		flush:   make(chan chan struct{}),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	registerOutput(func() { w.Close() })
+	return w
+}
+
+// Write queues a single encoded log entry. When the buffer is full the entry
+// is dropped, or with BlockWhenFull the write waits up to BlockTimeout first.
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	entry := append([]byte(nil), p...)
+
+	select {
+	case <-w.stop:
+		return 0, errWriterClosed
+	default:
+	}
+
+	select {
+	case w.entries <- entry:
+		return len(p), nil
+	default:
+	}
+
+	if w.options.BlockWhenFull {
+		timer := time.NewTimer(w.options.BlockTimeout)
+		defer timer.Stop()
+
+		select {
+		case w.entries <- entry:
+			return len(p), nil
+		case <-timer.C:
+		case <-w.stop:
+			return 0, errWriterClosed
+		}
+	}
+
+	w.dropped.Add(1)
+	return len(p), nil
+}
+
+// Sync sends all queued entries and waits for the delivery attempt to finish
+func (w *BatchWriter) Sync() error {
+	ack := make(chan struct{})
+	select {
+	case w.flush <- ack:
+		<-ack
+	case <-w.done:
+	}
+	return nil
+}
+
+// Close sends the queued entries and stops the background sender
+func (w *BatchWriter) Close() error {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the buffer was full
+// or delivery kept failing
+func (w *BatchWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *BatchWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.options.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([][]byte, 0, w.options.BatchSize)
+	for {
+		select {
+		case entry := <-w.entries:
+			batch = append(batch, entry)
+			if len(batch) >= w.options.BatchSize {
+				batch = w.deliver(batch)
+			}
+		case <-ticker.C:
+			batch = w.deliver(batch)
+		case ack := <-w.flush:
+			batch = w.deliver(w.drain(batch))
+			close(ack)
+		case <-w.stop:
+			w.deliver(w.drain(batch))
+			return
+		}
+	}
+}
+
+// drain moves all queued entries into batch, sending full batches on the way
+func (w *BatchWriter) drain(batch [][]byte) [][]byte {
+	for {
+		select {
+		case entry := <-w.entries:
+			batch = append(batch, entry)
+			if len(batch) >= w.options.BatchSize {
+				batch = w.deliver(batch)
+			}
+		default:
+			return batch
+		}
+	}
+}
+
+// deliver sends the batch with retries and returns the emptied batch
+func (w *BatchWriter) deliver(batch [][]byte) [][]byte {
+	if len(batch) == 0 {
+		return batch
+	}
+
+	for attempt := 0; attempt <= w.options.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * w.options.RetryBackoff)
+		}
+
+		err := w.send(batch)
+		if err == nil {
+			return batch[:0]
+		}
+		if errors.Is(err, ErrBatchRejected) {
+			break
+		}
+	}
+
+	w.dropped.Add(uint64(len(batch)))
+	return batch[:0]
+}
@@ -0,0 +1,120 @@
+package ginlogger
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchRecorder is a BatchSender recording the batches it was given
+type batchRecorder struct {
+	mu      sync.Mutex
+	batches [][]string
+	err     error
+}
+
+func (r *batchRecorder) send(batch [][]byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := make([]string, len(batch))
+	for i, entry := range batch {
+		entries[i] = string(entry)
+	}
+	r.batches = append(r.batches, entries)
+	return r.err
+}
+
+func (r *batchRecorder) calls() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.batches)
+}
+
+func TestBatchWriterBatches(t *testing.T) {
+	recorder := &batchRecorder{}
+	w := NewBatchWriter(recorder.send, BatchOptions{BatchSize: 2, FlushInterval: time.Hour})
+
+	for _, entry := range []string{"a", "b", "c"} {
+		w.Write([]byte(entry))
+	}
+	w.Close()
+
+	if len(recorder.batches) != 2 || len(recorder.batches[0]) != 2 || recorder.batches[1][0] != "c" {
+		t.Errorf("batches = %v, want [[a b] [c]]", recorder.batches)
+	}
+	if _, err := w.Write([]byte("d")); !errors.Is(err, errWriterClosed) {
+		t.Errorf("Write after Close = %v, want errWriterClosed", err)
+	}
+}
+
+func TestBatchWriterRetries(t *testing.T) {
+	recorder := &batchRecorder{err: errors.New("unavailable")}
+	w := NewBatchWriter(recorder.send, BatchOptions{MaxRetries: 2, RetryBackoff: time.Millisecond})
+
+	w.Write([]byte("a"))
+	w.Close()
+
+	if recorder.calls() != 3 {
+		t.Errorf("send calls = %d, want 3", recorder.calls())
+	}
+	if w.Dropped() != 1 {
+		t.Errorf("Dropped() = %d, want 1", w.Dropped())
+	}
+}
+
+func TestBatchWriterRejectedIsNotRetried(t *testing.T) {
+	recorder := &batchRecorder{err: ErrBatchRejected}
+	w := NewBatchWriter(recorder.send, BatchOptions{RetryBackoff: time.Millisecond})
+
+	w.Write([]byte("a"))
+	w.Close()
+
+	if recorder.calls() != 1 || w.Dropped() != 1 {
+		t.Errorf("send calls = %d, dropped = %d, want 1 and 1", recorder.calls(), w.Dropped())
+	}
+}
+
+func TestBatchWriterFullBuffer(t *testing.T) {
+	release := make(chan struct{})
+	send := func([][]byte) error {
+		<-release
+		return nil
+	}
+
+	t.Run("drop", func(t *testing.T) {
+		w := NewBatchWriter(send, BatchOptions{BatchSize: 1, BufferSize: 1})
+		// The first entry blocks the sender, the second fills the buffer
+		w.Write([]byte("a"))
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("b"))
+
+		start := time.Now()
+		w.Write([]byte("c"))
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("Write blocked for %v", elapsed)
+		}
+		if w.Dropped() != 1 {
+			t.Errorf("Dropped() = %d, want 1", w.Dropped())
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		w := NewBatchWriter(send, BatchOptions{BatchSize: 1, BufferSize: 1, BlockWhenFull: true, BlockTimeout: 50 * time.Millisecond})
+		w.Write([]byte("a"))
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("b"))
+
+		start := time.Now()
+		w.Write([]byte("c"))
+		if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+			t.Errorf("Write returned after %v, want it to wait for BlockTimeout", elapsed)
+		}
+		if w.Dropped() != 1 {
+			t.Errorf("Dropped() = %d, want 1", w.Dropped())
+		}
+	})
+
+	close(release)
+}
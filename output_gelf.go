@@ -0,0 +1,313 @@
+package ginlogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"os"
+	"regexp"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// GELF limits for UDP transport
+const (
+	defaultGELFChunkSize = 1420
+	gelfMaxChunks        = 128
+	gelfChunkHeaderSize  = 12
+)
+
+// Keys of the entry metadata in the intermediate JSON, renamed by GELFWriter.
+// They are prefixed so that they never collide with logged fields.
+const (
+	gelfLevelKey      = "gelf.level"
+	gelfTimeKey       = "gelf.timestamp"
+	gelfMessageKey    = "gelf.short_message"
+	gelfCallerKey     = "gelf.caller"
+	gelfStacktraceKey = "gelf.full_message"
+)
+
+// gelfInvalidKeyChars matches characters not allowed in GELF field names
+var gelfInvalidKeyChars = regexp.MustCompile(`[^\w.\-]`)
+
+// GELFOptions configures shipping log entries to Graylog
+type GELFOptions struct {
+	// Level is the minimum level shipped to Graylog (default info)
+	Level string
+	// Protocol is "udp" (default) or "tcp"
+	Protocol string
+	// Host is reported as the GELF host (default os.Hostname)
+	Host string
+	// ChunkSize is the maximum UDP datagram size; larger messages are split
+	// into up to 128 chunks and dropped beyond that (default 1420)
+	ChunkSize int
+	// Compress gzips UDP messages before chunking. TCP messages are never
+	// compressed, as the GELF TCP framing does not allow it.
+	Compress bool
+	// DialTimeout bounds connecting to Graylog (default 5s)
+	DialTimeout time.Duration
+	// BufferSize bounds the number of queued entries. When Graylog cannot keep
+	// up, new entries are dropped rather than blocking requests (default 10000).
+	BufferSize int
+	// MaxRetries is the number of reconnects and resends of a failed message
+	// (default 3, negative disables retries)
+	MaxRetries int
+}
+
+// GELFWriter converts JSON log lines into GELF messages (version, host,
+// short_message, timestamp, level and "_"-prefixed additional fields) and
+// sends them to Graylog from a background goroutine. The connection is
+// established on the first send and re-established after errors.
+type GELFWriter struct {
+	*BatchWriter
+	addr    string
+	options GELFOptions
+	// conn is only used by the BatchWriter goroutine, and by Close once it
+	// has stopped
+	conn net.Conn
+}
+
+// WithGELFOutput returns a Logger that writes entries to base and also ships
+// them in GELF to the Graylog input at addr (e.g. "graylog:12201"). A nil base
+// ships to Graylog only.
+func WithGELFOutput(base Logger, addr string, options GELFOptions) Logger {
+	writer := NewGELFWriter(addr, options)
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(gelfEncoderConfig()), writer, boostEnabler{parseLevel(options.Level)})
+	return TeeLogger(base, NewZapLogger(zap.New(core, zap.AddCaller())))
+}
+
+// NewGELFWriter returns a GELFWriter sending to addr
+func NewGELFWriter(addr string, options GELFOptions) *GELFWriter {
+	if options.Protocol == "" {
+		options.Protocol = "udp"
+	}
+
+	if options.Host == "" {
+		options.Host, _ = os.Hostname()
+	}
+
+	if options.ChunkSize <= gelfChunkHeaderSize {
+		options.ChunkSize = defaultGELFChunkSize
+	}
+
+	if options.DialTimeout <= 0 {
+		options.DialTimeout = 5 * time.Second
+	}
+
+	w := &GELFWriter{addr: addr, options: options}
+	// GELF has no batch framing, so messages are handed over one at a time
+	w.BatchWriter = NewBatchWriter(w.sendEntry, BatchOptions{
+		BatchSize:    1,
+		BufferSize:   options.BufferSize,
+		MaxRetries:   options.MaxRetries,
+		RetryBackoff: 100 * time.Millisecond,
+	})
+	return w
+}
+
+// gelfEncoderConfig encodes the entry metadata the way GELF expects it:
+// Unix timestamps in seconds and syslog severities
+func gelfEncoderConfig() zapcore.EncoderConfig {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.LevelKey = gelfLevelKey
+	encoderConfig.TimeKey = gelfTimeKey
+	encoderConfig.MessageKey = gelfMessageKey
+	encoderConfig.CallerKey = gelfCallerKey
+	encoderConfig.StacktraceKey = gelfStacktraceKey
+	encoderConfig.EncodeTime = zapcore.EpochTimeEncoder
+	encoderConfig.EncodeLevel = func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendInt(syslogSeverity(level))
+	}
+	return encoderConfig
+}
+
+// syslogSeverity maps a zap level to the syslog severity used by GELF
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel:
+		return 2
+	case zapcore.PanicLevel:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// Close sends the queued messages, stops the background sender and closes
+// the connection to Graylog
+func (w *GELFWriter) Close() error {
+	w.BatchWriter.Close()
+	return w.closeConn()
+}
+
+// sendEntry converts a queued JSON log line into a GELF message and sends it,
+// reconnecting on the next attempt after an error
+func (w *GELFWriter) sendEntry(batch [][]byte) error {
+	for _, entry := range batch {
+		message, err := w.convert(entry)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrBatchRejected, err)
+		}
+
+		if err := w.send(message); err != nil {
+			w.closeConn()
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *GELFWriter) closeConn() error {
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// convert renames the metadata keys of a JSON log line to their GELF names and
+// prefixes all other fields with "_". GELF field values must be strings or
+// numbers, so booleans, objects and arrays are sent as strings.
+func (w *GELFWriter) convert(line []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, errors.New("ginlogger: GELF input is not a JSON object")
+	}
+
+	message := map[string]any{
+		"version": "1.1",
+		"host":    w.options.Host,
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case gelfLevelKey:
+			message["level"] = json.Number(value)
+			continue
+		case gelfTimeKey:
+			message["timestamp"] = json.Number(value)
+			continue
+		case gelfMessageKey:
+			message["short_message"] = gelfString(value)
+			continue
+		case gelfStacktraceKey:
+			message["full_message"] = gelfString(value)
+			continue
+		case gelfCallerKey:
+			key = "caller"
+		}
+
+		key = "_" + gelfInvalidKeyChars.ReplaceAllString(key, "_")
+		if key == "_id" {
+			// Reserved by GELF
+			key = "_id_"
+		}
+
+		switch {
+		case bytes.Equal(value, []byte("null")):
+		case value[0] == '"':
+			message[key] = gelfString(value)
+		case value[0] == '-' || (value[0] >= '0' && value[0] <= '9'):
+			message[key] = json.Number(value)
+		default:
+			message[key] = string(value)
+		}
+	}
+
+	return json.Marshal(message)
+}
+
+// gelfString decodes a JSON string, falling back to the raw JSON
+func gelfString(value json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return string(value)
+	}
+	return s
+}
+
+// send writes message over the current connection, dialing if needed
+func (w *GELFWriter) send(message []byte) error {
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.options.Protocol, w.addr, w.options.DialTimeout)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+	}
+
+	if w.options.Protocol != "udp" {
+		// GELF TCP frames are terminated by a null byte
+		_, err := w.conn.Write(append(message, 0))
+		return err
+	}
+
+	if w.options.Compress {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		gz.Write(message)
+		gz.Close()
+		message = buf.Bytes()
+	}
+
+	if len(message) <= w.options.ChunkSize {
+		_, err := w.conn.Write(message)
+		return err
+	}
+
+	return w.sendChunked(message)
+}
+
+// sendChunked splits message into GELF chunks: magic bytes, message ID,
+// sequence number and count, followed by the payload
+func (w *GELFWriter) sendChunked(message []byte) error {
+	payloadSize := w.options.ChunkSize - gelfChunkHeaderSize
+	count := (len(message) + payloadSize - 1) / payloadSize
+	if count > gelfMaxChunks {
+		return fmt.Errorf("%w: GELF message of %d bytes exceeds %d chunks", ErrBatchRejected, len(message), gelfMaxChunks)
+	}
+
+	chunk := make([]byte, 0, w.options.ChunkSize)
+	id := rand.Uint64()
+	for i := range count {
+		payload := message[i*payloadSize : min((i+1)*payloadSize, len(message))]
+
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = binary.BigEndian.AppendUint64(chunk, id)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, payload...)
+
+		if _, err := w.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package ginlogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenGELF returns a local UDP listener standing in for a Graylog input
+func listenGELF(t *testing.T) *net.UDPConn {
+	t.Helper()
+
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// readDatagram returns the next datagram received by conn
+func readDatagram(t *testing.T, conn *net.UDPConn) []byte {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 65536)
+	n, err := conn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf[:n]
+}
+
+func TestWithGELFOutput(t *testing.T) {
+	conn := listenGELF(t)
+	logger := WithGELFOutput(nil, conn.LocalAddr().String(), GELFOptions{Host: "api-1"})
+
+	logger.Warn("Slow request", String("path", "/users"), Int("status", 200), Bool("cached", true))
+	logger.Sync()
+
+	var message map[string]any
+	if err := json.Unmarshal(readDatagram(t, conn), &message); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]any{
+		"version":       "1.1",
+		"host":          "api-1",
+		"short_message": "Slow request",
+		"level":         float64(4),
+		"_path":         "/users",
+		"_status":       float64(200),
+		"_cached":       "true",
+	}
+	for key, value := range want {
+		if message[key] != value {
+			t.Errorf("%s = %v, want %v", key, message[key], value)
+		}
+	}
+	if _, ok := message["timestamp"].(float64); !ok {
+		t.Errorf("timestamp = %v, want a number", message["timestamp"])
+	}
+	if _, ok := message["_caller"]; !ok {
+		t.Error("caller missing")
+	}
+}
+
+func TestGELFWriterChunks(t *testing.T) {
+	conn := listenGELF(t)
+	w := NewGELFWriter(conn.LocalAddr().String(), GELFOptions{ChunkSize: 512, Compress: true})
+	defer w.Close()
+
+	// Random-looking content so that gzip cannot shrink it below one chunk
+	var long strings.Builder
+	for i := range 400 {
+		long.WriteString(time.Duration(i * 7919).String())
+	}
+	line, _ := json.Marshal(map[string]string{gelfMessageKey: long.String()})
+	w.Write(line)
+	w.Sync()
+
+	first := readDatagram(t, conn)
+	if first[0] != 0x1e || first[1] != 0x0f {
+		t.Fatalf("first datagram starts with %x, want the GELF chunk magic bytes", first[:2])
+	}
+
+	count := int(first[11])
+	chunks := map[byte][]byte{first[10]: first[12:]}
+	for len(chunks) < count {
+		chunk := readDatagram(t, conn)
+		chunks[chunk[10]] = chunk[12:]
+	}
+
+	var compressed []byte
+	for i := range count {
+		compressed = append(compressed, chunks[byte(i)]...)
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, _ := io.ReadAll(gz)
+	if !bytes.Contains(message, []byte(`"short_message":"`+long.String()[:32])) {
+		t.Errorf("reassembled message = %.100s..., want the long short_message", message)
+	}
+}
+
+func TestGELFWriterDoesNotBlockWrites(t *testing.T) {
+	// Nothing accepts connections on a closed TCP listener
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	w := NewGELFWriter(addr, GELFOptions{Protocol: "tcp", MaxRetries: -1})
+	logger := NewWriterLogger(w, "info")
+
+	start := time.Now()
+	for range 100 {
+		logger.Info("Request")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("writes took %v with Graylog unavailable", elapsed)
+	}
+
+	w.Close()
+	if w.Dropped() != 100 {
+		t.Errorf("Dropped() = %d, want 100", w.Dropped())
+	}
+}
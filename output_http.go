@@ -0,0 +1,89 @@
+package ginlogger
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTPOutputOptions configures shipping log entries to an HTTP collector
+type HTTPOutputOptions struct {
+	// Level is the minimum level shipped to the collector (default info)
+	Level string
+	// BatchSize is the number of entries per POST (default 100)
+	BatchSize int
+	// FlushInterval is the maximum time an entry waits before being sent (default 5s)
+	FlushInterval time.Duration
+	// MaxRetries is the number of retries on network errors and 5xx responses
+	// (default 3, negative disables retries)
+	MaxRetries int
+	// RetryBackoff is the delay before the first retry, growing linearly (default 500ms)
+	RetryBackoff time.Duration
+	// AuthHeader is sent as the Authorization header, e.g. "Bearer <token>"
+	AuthHeader string
+	// BufferSize bounds the number of queued entries. When the collector cannot
+	// keep up, new entries are dropped rather than blocking requests (default 10000).
+	BufferSize int
+	// Client defaults to an http.Client with a 10s timeout
+	Client *http.Client
+}
+
+// HTTPWriter batches log lines and POSTs them as NDJSON to a collector
+// (e.g. a Logstash HTTP input or a custom ingest API)
+type HTTPWriter struct {
+	*BatchWriter
+	endpoint string
+	options  HTTPOutputOptions
+}
+
+// WithHTTPOutput returns a Logger that writes entries to base and also ships
+// them to the HTTP collector at endpoint. A nil base ships to the collector only.
+func WithHTTPOutput(base Logger, endpoint string, options HTTPOutputOptions) Logger {
+	return TeeLogger(base, NewWriterLogger(NewHTTPWriter(endpoint, options), options.Level))
+}
+
+// NewHTTPWriter returns an HTTPWriter shipping to endpoint
+func NewHTTPWriter(endpoint string, options HTTPOutputOptions) *HTTPWriter {
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	w := &HTTPWriter{endpoint: endpoint, options: options}
+	w.BatchWriter = NewBatchWriter(w.post, BatchOptions{
+		BatchSize:     options.BatchSize,
+		FlushInterval: options.FlushInterval,
+		BufferSize:    options.BufferSize,
+		MaxRetries:    options.MaxRetries,
+		RetryBackoff:  options.RetryBackoff,
+	})
+	return w
+}
+
+// post sends the batch as one NDJSON body. 4xx responses are not retried, the
+// collector rejected the payload.
+func (w *HTTPWriter) post(batch [][]byte) error {
+	req, err := http.NewRequest(http.MethodPost, w.endpoint, bytes.NewReader(bytes.Join(batch, nil)))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrBatchRejected, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if w.options.AuthHeader != "" {
+		req.Header.Set("Authorization", w.options.AuthHeader)
+	}
+
+	resp, err := w.options.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return fmt.Errorf("ginlogger: collector responded %d", resp.StatusCode)
+	case resp.StatusCode >= 400:
+		return fmt.Errorf("%w: collector responded %d", ErrBatchRejected, resp.StatusCode)
+	}
+	return nil
+}
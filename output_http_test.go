@@ -0,0 +1,128 @@
+package ginlogger
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// collector is an HTTP log collector recording the NDJSON lines of each POST
+type collector struct {
+	mu       sync.Mutex
+	requests [][]string
+	auth     string
+	statuses []int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lines []string
+	scanner := bufio.NewScanner(r.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	c.requests = append(c.requests, lines)
+	c.auth = r.Header.Get("Authorization")
+
+	if len(c.statuses) > 0 {
+		w.WriteHeader(c.statuses[0])
+		c.statuses = c.statuses[1:]
+	}
+}
+
+func TestWithHTTPOutput(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	base, buf := newTestLogger()
+	logger := WithHTTPOutput(base, server.URL, HTTPOutputOptions{Level: "warn"})
+
+	logger.Info("Request completed")
+	logger.Warn("Client error")
+	logger.Sync()
+
+	if entries := buf.entries(t); len(entries) != 2 {
+		t.Fatalf("base entries = %v, want both", entries)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.requests) != 1 || len(c.requests[0]) != 1 || !strings.Contains(c.requests[0][0], `"msg":"Client error"`) {
+		t.Fatalf("requests = %v, want only the warning shipped", c.requests)
+	}
+}
+
+func TestHTTPWriterDropsWhenCollectorIsDown(t *testing.T) {
+	// Nothing listens on a closed server
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	w := NewHTTPWriter(server.URL, HTTPOutputOptions{BufferSize: 10, MaxRetries: -1})
+	start := time.Now()
+	for range 100 {
+		w.Write([]byte("{}\n"))
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("writes took %v with the collector down", elapsed)
+	}
+
+	w.Close()
+	if w.Dropped() != 100 {
+		t.Fatalf("Dropped() = %d, want every entry dropped", w.Dropped())
+	}
+}
+
+func TestHTTPWriterBatches(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	w := NewHTTPWriter(server.URL, HTTPOutputOptions{BatchSize: 2, FlushInterval: time.Hour, AuthHeader: "Bearer token"})
+	logger := NewWriterLogger(w, "info")
+	logger.Info("first")
+	logger.Info("second")
+	logger.Info("third")
+	w.Close()
+
+	if len(c.requests) != 2 || len(c.requests[0]) != 2 || len(c.requests[1]) != 1 {
+		t.Fatalf("requests = %v, want batches of 2 and 1 entries", c.requests)
+	}
+	if c.auth != "Bearer token" {
+		t.Errorf("Authorization = %q, want the AuthHeader", c.auth)
+	}
+}
+
+func TestHTTPWriterRetries(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusServiceUnavailable, http.StatusOK}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	w := NewHTTPWriter(server.URL, HTTPOutputOptions{RetryBackoff: time.Millisecond})
+	w.Write([]byte("{}\n"))
+	w.Close()
+
+	if len(c.requests) != 2 || w.Dropped() != 0 {
+		t.Errorf("requests = %d, dropped = %d, want a retry after the 503", len(c.requests), w.Dropped())
+	}
+}
+
+func TestHTTPWriterDoesNotRetryClientErrors(t *testing.T) {
+	c := &collector{statuses: []int{http.StatusBadRequest}}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	w := NewHTTPWriter(server.URL, HTTPOutputOptions{RetryBackoff: time.Millisecond})
+	w.Write([]byte("{}\n"))
+	w.Close()
+
+	if len(c.requests) != 1 || w.Dropped() != 1 {
+		t.Errorf("requests = %d, dropped = %d, want the batch dropped after the 400", len(c.requests), w.Dropped())
+	}
+}
@@ -0,0 +1,50 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithLevelRouting(t *testing.T) {
+	access, errs := &syncBuffer{}, &syncBuffer{}
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: WithLevelRouting(access, errs, zapcore.WarnLevel)}))
+	r.GET("/ok", ok)
+	r.GET("/fail", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	accessEntries, errorEntries := access.entries(t), errs.entries(t)
+	if len(accessEntries) != 1 || accessEntries[0]["status"] != float64(200) {
+		t.Errorf("access entries = %v, want the 200", accessEntries)
+	}
+	if len(errorEntries) != 1 || errorEntries[0]["status"] != float64(500) {
+		t.Errorf("error entries = %v, want the 500", errorEntries)
+	}
+}
+
+func TestWithLevelRoutingMinimumLevel(t *testing.T) {
+	t.Cleanup(func() { BoostLogging(zapcore.InfoLevel, 0) })
+
+	access, errs := &syncBuffer{}, &syncBuffer{}
+	logger := WithLevelRoutingConfig(LevelRoutingConfig{AccessWriter: access, ErrorWriter: errs, Level: "info"})
+
+	logger.Debug("filtered")
+	BoostLogging(zapcore.DebugLevel, time.Minute)
+	logger.Debug("boosted")
+	logger.Warn("warning")
+
+	accessEntries := access.entries(t)
+	if len(accessEntries) != 1 || accessEntries[0]["msg"] != "boosted" || accessEntries[0]["level"] != "debug" {
+		t.Errorf("access entries = %v, want only the boosted debug entry", accessEntries)
+	}
+	if errorEntries := errs.entries(t); len(errorEntries) != 1 || errorEntries[0]["msg"] != "warning" {
+		t.Errorf("error entries = %v, want the warning", errorEntries)
+	}
+}
@@ -0,0 +1,36 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestPerformanceLoggerThreshold(t *testing.T) {
+	logger, buf := newTestLogger()
+	var slow []time.Duration
+	middleware := PerformanceLoggerWithConfig(PerformanceLoggerConfig{
+		Logger:    logger,
+		Threshold: 20 * time.Millisecond,
+		OnSlowRequest: func(c *gin.Context, latency time.Duration) {
+			slow = append(slow, latency)
+		},
+	})
+
+	serve(httptest.NewRequest(http.MethodGet, "/fast", nil), "/:path", ok, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/slow", nil), "/:path", func(c *gin.Context) {
+		time.Sleep(30 * time.Millisecond)
+		c.Status(http.StatusOK)
+	}, middleware)
+
+	entries := buf.entries(t)
+	if len(entries) != 1 || entries[0]["msg"] != "Slow request detected" || entries[0]["path"] != "/slow" {
+		t.Fatalf("entries = %v, want a warning for /slow only", entries)
+	}
+	if len(slow) != 1 || slow[0] < 30*time.Millisecond {
+		t.Errorf("OnSlowRequest calls = %v, want one with the slow latency", slow)
+	}
+}
@@ -0,0 +1,60 @@
+package ginlogger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// phaseMarks records named points in the request lifecycle
+type phaseMarks struct {
+	mu     sync.Mutex
+	names  []string
+	offset []time.Duration
+}
+
+// MarkPhase records that the request reached the named phase (e.g. "auth_done").
+// StructuredLogger emits all marks as a phases object mapping each name to the
+// time elapsed since the request started, showing where latency accrues.
+func MarkPhase(c *gin.Context, name string) {
+	start := c.GetTime(requestStartKey)
+	if start.IsZero() {
+		return
+	}
+
+	marks, _ := c.Get(phasesKey)
+	phases, ok := marks.(*phaseMarks)
+	if !ok {
+		phases = &phaseMarks{}
+		c.Set(phasesKey, phases)
+	}
+
+	phases.mu.Lock()
+	defer phases.mu.Unlock()
+	phases.names = append(phases.names, name)
+	phases.offset = append(phases.offset, time.Since(start))
+}
+
+// phasesField returns the phases recorded via MarkPhase
+func phasesField(c *gin.Context) (zap.Field, bool) {
+	marks, _ := c.Get(phasesKey)
+	phases, ok := marks.(*phaseMarks)
+	if !ok {
+		return zap.Skip(), false
+	}
+
+	return zap.Object("phases", phases), true
+}
+
+func (p *phaseMarks) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for i, name := range p.names {
+		encoder.AddDuration(name, p.offset[i])
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMarkPhase(t *testing.T) {
+	logger, buf := newTestLogger()
+	handler := func(c *gin.Context) {
+		MarkPhase(c, "auth_done")
+		MarkPhase(c, "db_done")
+		c.Status(http.StatusOK)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", handler, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	phases, ok := buf.entries(t)[0]["phases"].(map[string]any)
+	if !ok {
+		t.Fatalf("phases missing: %s", buf.String())
+	}
+	auth, _ := phases["auth_done"].(float64)
+	db, _ := phases["db_done"].(float64)
+	if _, ok := phases["auth_done"]; !ok || db < auth {
+		t.Errorf("phases = %v, want auth_done and a later db_done", phases)
+	}
+}
+
+func TestMarkPhaseWithoutStructuredLogger(t *testing.T) {
+	logger, buf := newTestLogger()
+	handler := func(c *gin.Context) {
+		// No request start recorded, so the mark is ignored
+		MarkPhase(c, "auth_done")
+		if _, exists := c.Get(phasesKey); exists {
+			t.Error("phase recorded without StructuredLogger")
+		}
+		c.Status(http.StatusOK)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", handler)
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	if _, ok := buf.entries(t)[0]["phases"]; ok {
+		t.Errorf("phases logged without marks: %s", buf.String())
+	}
+}
@@ -0,0 +1,113 @@
+package ginlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// DefaultPIIPatterns are the patterns redacted by DetectPII. More specific
+// patterns come first so that e.g. card numbers are not taken for phone numbers.
+// Append to a copy to extend the set via StructuredLoggerConfig.PIIPatterns.
+var DefaultPIIPatterns = []*regexp.Regexp{
+	// Email address
+	regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	// US social security number
+	regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	// Credit card number, optionally grouped by spaces or dashes
+	regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	// IPv4 address
+	regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`),
+	// Phone number
+	regexp.MustCompile(`(?:\+\d{1,3}[ .-]?)?\(?\b\d{3}\)?[ .-]?\d{3}[ .-]?\d{4}\b`),
+}
+
+// piiExemptFields are identifiers that look like PII (digit runs) but are not
+var piiExemptFields = map[string]bool{
+	"ip":         true,
+	"request_id": true,
+	"trace_id":   true,
+	"span_id":    true,
+}
+
+// redactPII masks PII pattern matches in string fields, returning the number
+// of redacted matches. Structured JSON bodies are redacted by redactJSONPII
+// before their field is built.
+func redactPII(fields []zap.Field, patterns []*regexp.Regexp) int {
+	count := 0
+	for i, field := range fields {
+		if piiExemptFields[field.Key] || field.Type != zapcore.StringType {
+			continue
+		}
+
+		redacted, n := redactPatterns(field.String, patterns)
+		if n > 0 {
+			fields[i] = zap.String(field.Key, redacted)
+			count += n
+		}
+	}
+	return count
+}
+
+// redactJSONPII masks PII pattern matches in the string and number values of
+// a JSON document, returning the redacted document and the number of matches.
+// Matched numbers become strings, so the document stays valid JSON.
+func redactJSONPII(body json.RawMessage, patterns []*regexp.Regexp) (json.RawMessage, int) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return body, 0
+	}
+
+	value, count := redactJSONValue(value, patterns)
+	if count == 0 {
+		return body, 0
+	}
+
+	redacted, err := json.Marshal(value)
+	if err != nil {
+		return body, 0
+	}
+	return redacted, count
+}
+
+func redactJSONValue(value any, patterns []*regexp.Regexp) (any, int) {
+	count := 0
+	switch v := value.(type) {
+	case string:
+		return redactPatterns(v, patterns)
+	case json.Number:
+		if redacted, n := redactPatterns(v.String(), patterns); n > 0 {
+			return redacted, n
+		}
+	case []any:
+		for i, item := range v {
+			var n int
+			v[i], n = redactJSONValue(item, patterns)
+			count += n
+		}
+	case map[string]any:
+		for key, item := range v {
+			var n int
+			v[key], n = redactJSONValue(item, patterns)
+			count += n
+		}
+	}
+	return value, count
+}
+
+func redactPatterns(value string, patterns []*regexp.Regexp) (string, int) {
+	count := 0
+	for _, pattern := range patterns {
+		value = pattern.ReplaceAllStringFunc(value, func(string) string {
+			count++
+			return redactedValue
+		})
+	}
+	return value, count
+}
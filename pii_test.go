@@ -0,0 +1,88 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDetectPII(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		structured  bool
+	}{
+		{"string body", "text/plain", false},
+		{"structured json body", "application/json", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			middleware := StructuredLogger(StructuredLoggerConfig{
+				Logger:             logger,
+				LogRequestBody:     true,
+				StructuredJSONBody: tt.structured,
+				DetectPII:          true,
+			})
+
+			body := `{"email":"jane.doe@example.com","phone":"555-123-4567","fax":5551234568,"note":"hi"}`
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+			req.Header.Set("Content-Type", tt.contentType)
+			serve(req, "/", ok, middleware)
+
+			entry := findEntry(t, buf.entries(t), "Request completed")
+			line := buf.String()
+			for _, pii := range []string{"jane.doe@example.com", "555-123-4567", "5551234568"} {
+				if strings.Contains(line, pii) {
+					t.Errorf("%q not redacted: %s", pii, line)
+				}
+			}
+			if entry["pii_redacted_count"] != float64(3) {
+				t.Errorf("pii_redacted_count = %v, want 3", entry["pii_redacted_count"])
+			}
+
+			if tt.structured {
+				object, isObject := entry["request_body"].(map[string]any)
+				if !isObject || object["email"] != redactedValue || object["note"] != "hi" {
+					t.Errorf("request_body = %#v, want nested object with redacted email", entry["request_body"])
+				}
+			}
+		})
+	}
+}
+
+func TestDetectPIICustomPatterns(t *testing.T) {
+	logger, buf := newTestLogger()
+	patterns := append([]*regexp.Regexp{regexp.MustCompile(`EMP-\d{6}`)}, DefaultPIIPatterns...)
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:         logger,
+		LogRequestBody: true,
+		DetectPII:      true,
+		PIIPatterns:    patterns,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("employee EMP-123456"))
+	serve(req, "/", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	if entry["request_body"] != "employee "+redactedValue {
+		t.Errorf("request_body = %q, want custom pattern redacted", entry["request_body"])
+	}
+}
+
+func TestDetectPIIExemptsIdentifiers(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogClientIP: true, DetectPII: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "192.0.2.10:1234"
+	serve(req, "/", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	if entry["ip"] != "192.0.2.10" {
+		t.Errorf("ip = %v, want it exempt from redaction", entry["ip"])
+	}
+}
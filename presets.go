@@ -0,0 +1,44 @@
+package ginlogger
+
+import (
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMiddleware returns the recommended middleware stack in the correct
+// order: request ID, security, performance, structured logging, error logging
+// and recovery. Recovery comes last so that the middleware before it still
+// logs requests whose handler panicked, as a 500. Use it as
+// r.Use(ginlogger.DefaultMiddleware(config)...).
+func DefaultMiddleware(config StructuredLoggerConfig) []gin.HandlerFunc {
+	return []gin.HandlerFunc{
+		RequestIDMiddleware(),
+		SecurityLogger(),
+		PerformanceLogger(),
+		StructuredLogger(config),
+		ErrorLogger(),
+		RecoveryLogger(),
+	}
+}
+
+// ProductionMiddleware returns the default stack with production settings:
+// UTC timestamps, client IP and user agent logging, and redacted credentials
+func ProductionMiddleware(config StructuredLoggerConfig) []gin.HandlerFunc {
+	config.UTC = true
+	config.LogClientIP = true
+	config.LogUserAgent = true
+	config.RedactHeaders = append(slices.Clone(config.RedactHeaders), "Authorization", "Cookie")
+	return DefaultMiddleware(config)
+}
+
+// DevelopmentMiddleware returns the default stack with verbose settings for
+// local debugging: request bodies, client details and status texts
+func DevelopmentMiddleware(config StructuredLoggerConfig) []gin.HandlerFunc {
+	config.LogClientIP = true
+	config.LogUserAgent = true
+	config.LogReferer = true
+	config.LogRequestBody = true
+	config.LogStatusText = true
+	return DefaultMiddleware(config)
+}
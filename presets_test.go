@@ -0,0 +1,69 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestProductionMiddleware(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(ProductionMiddleware(StructuredLoggerConfig{Logger: logger, LogHeaders: []string{"Authorization"}})...)
+	r.GET("/", ok)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("User-Agent", "curl/8.0")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	// The request ID middleware runs before StructuredLogger
+	if entry["request_id"] == nil || entry["request_id"] != w.Header().Get("X-Request-ID") {
+		t.Errorf("request_id = %v, want the generated %q", entry["request_id"], w.Header().Get("X-Request-ID"))
+	}
+	if entry["header_Authorization"] != redactedValue {
+		t.Errorf("header_Authorization = %v, want it redacted", entry["header_Authorization"])
+	}
+	if entry["ip"] == nil || entry["user_agent"] != "curl/8.0" {
+		t.Errorf("entry = %v, want the client IP and user agent", entry)
+	}
+	if timestamp, _ := entry["timestamp"].(string); !strings.HasSuffix(timestamp, "Z") {
+		t.Errorf("timestamp = %v, want UTC", entry["timestamp"])
+	}
+}
+
+func TestDevelopmentMiddleware(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(DevelopmentMiddleware(StructuredLoggerConfig{Logger: logger})...)
+	r.POST("/", func(c *gin.Context) { c.Status(http.StatusBadRequest) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", strings.NewReader("name=gopher")))
+
+	entry := findEntry(t, buf.entries(t), "Client error")
+	if entry["request_body"] != "name=gopher" || entry["status_text"] != "Bad Request" {
+		t.Fatalf("entry = %v, want the request body and status text", entry)
+	}
+}
+
+func TestDefaultMiddlewareRecovers(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(DefaultMiddleware(StructuredLoggerConfig{Logger: logger})...)
+	r.GET("/", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500 after the panic", w.Code)
+	}
+	findEntry(t, buf.entries(t), "Server error")
+	findEntry(t, entries(), "Panic recovered")
+}
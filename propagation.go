@@ -0,0 +1,140 @@
+package ginlogger
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// requestIDContextKey is the context.Context key of the request ID
+type requestIDContextKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, if any
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// contextField carries ctx in an entry without encoding it, so that loggers
+// correlating entries with the request (e.g. by its trace) can find it
+func contextField(ctx context.Context) zap.Field {
+	return zap.Field{Type: zapcore.SkipType, Interface: ctx}
+}
+
+// ContextFromFields returns the request context carried by the fields of an
+// entry logged by StructuredLogger, or nil. Encoders skip it; loggers that
+// emit entries to context-aware backends (such as the OpenTelemetry log
+// bridge) use it to correlate the entry with the request's trace.
+func ContextFromFields(fields []zap.Field) context.Context {
+	for _, field := range fields {
+		if ctx, ok := field.Interface.(context.Context); ok && field.Type == zapcore.SkipType {
+			return ctx
+		}
+	}
+	return nil
+}
+
+// subrequestContextKey is the context.Context key of the subrequest info
+type subrequestContextKey struct{}
+
+type subrequestInfo struct {
+	parentRequestID string
+	id              uint64
+}
+
+// subrequestMu guards creating the per-request subrequest counter
+var subrequestMu sync.Mutex
+
+// SubrequestContext returns a context for one downstream call of a fan-out,
+// derived from the request context. It carries the request ID (see
+// PropagateRequestID) and a subrequest ID unique within the request, so that
+// logs of parallel calls can be correlated via SubrequestLogger. It is safe
+// for concurrent use.
+func SubrequestContext(c *gin.Context) context.Context {
+	subrequestMu.Lock()
+	value, _ := c.Get(subrequestsKey)
+	counter, _ := value.(*atomic.Uint64)
+	if counter == nil {
+		counter = new(atomic.Uint64)
+		c.Set(subrequestsKey, counter)
+	}
+	subrequestMu.Unlock()
+
+	requestID := OutboundRequestID(c)
+	ctx := ContextWithRequestID(c.Request.Context(), requestID)
+	return context.WithValue(ctx, subrequestContextKey{}, subrequestInfo{
+		parentRequestID: requestID,
+		id:              counter.Add(1),
+	})
+}
+
+// SubrequestLogger returns the global logger with parent_request_id and
+// subrequest_id of a context created by SubrequestContext
+func SubrequestLogger(ctx context.Context) Logger {
+	info, ok := ctx.Value(subrequestContextKey{}).(subrequestInfo)
+	if !ok {
+		return GetLogger()
+	}
+
+	return GetLogger().With(
+		zap.String("parent_request_id", info.parentRequestID),
+		zap.Uint64("subrequest_id", info.id),
+	)
+}
+
+// OutboundRequestID returns the request ID to propagate to downstream calls
+// made while handling c
+func OutboundRequestID(c *gin.Context) string {
+	if requestID := c.GetString("request_id"); requestID != "" {
+		return requestID
+	}
+	return RequestIDFromContext(c.Request.Context())
+}
+
+// requestIDTransport injects the request ID into outbound requests
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+// PropagateRequestID wraps base (http.DefaultTransport when nil) so that
+// outbound requests created with the inbound request context, e.g.
+//
+//	http.NewRequestWithContext(c.Request.Context(), ...)
+//
+// carry its ID in the X-Request-ID header, linking inbound and downstream logs
+func PropagateRequestID(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &requestIDTransport{base: base}
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestID := RequestIDFromContext(req.Context())
+	if requestID == "" || req.Header.Get("X-Request-ID") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	// A RoundTripper must not modify the caller's request
+	req = req.Clone(req.Context())
+	req.Header.Set("X-Request-ID", requestID)
+
+	GetLogger().Debug("Propagating request ID",
+		zap.String("request_id", requestID),
+		zap.String("method", req.Method),
+		zap.String("host", req.URL.Host),
+		zap.String("path", req.URL.Path),
+	)
+
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,139 @@
+package ginlogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestPropagateRequestID(t *testing.T) {
+	var received []string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Request-ID"))
+	}))
+	defer downstream.Close()
+
+	client := &http.Client{Transport: PropagateRequestID(nil)}
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/", func(c *gin.Context) {
+		req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		resp.Body.Close()
+
+		// An explicitly set ID is kept
+		req, _ = http.NewRequestWithContext(c.Request.Context(), http.MethodGet, downstream.URL, nil)
+		req.Header.Set("X-Request-ID", "explicit")
+		if resp, err = client.Do(req); err == nil {
+			resp.Body.Close()
+		}
+
+		if OutboundRequestID(c) != "req-1" {
+			t.Errorf("OutboundRequestID() = %q, want req-1", OutboundRequestID(c))
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(received) != 2 || received[0] != "req-1" || received[1] != "explicit" {
+		t.Fatalf("downstream received %v, want [req-1 explicit]", received)
+	}
+}
+
+func TestPropagateRequestIDWithoutID(t *testing.T) {
+	var header string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("X-Request-ID")
+	}))
+	defer downstream.Close()
+
+	resp, err := (&http.Client{Transport: PropagateRequestID(nil)}).Get(downstream.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if header != "" {
+		t.Fatalf("X-Request-ID = %q, want none outside a request", header)
+	}
+}
+
+func TestSubrequestContext(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+
+	const fanOut = 8
+	r := gin.New()
+	r.Use(RequestIDMiddleware())
+	r.GET("/", func(c *gin.Context) {
+		var wg sync.WaitGroup
+		for range fanOut {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx := SubrequestContext(c)
+				if RequestIDFromContext(ctx) != "req-1" {
+					t.Errorf("subrequest context carries %q, want req-1", RequestIDFromContext(ctx))
+				}
+				SubrequestLogger(ctx).Info("Calling downstream")
+			}()
+		}
+		wg.Wait()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	seen := make(map[float64]bool)
+	for _, entry := range entries() {
+		if entry["msg"] != "Calling downstream" {
+			continue
+		}
+		if entry["parent_request_id"] != "req-1" {
+			t.Errorf("parent_request_id = %v, want req-1", entry["parent_request_id"])
+		}
+		seen[entry["subrequest_id"].(float64)] = true
+	}
+	for id := 1; id <= fanOut; id++ {
+		if !seen[float64(id)] {
+			t.Errorf("subrequest_id %d missing, want IDs 1 to %d: %v", id, fanOut, seen)
+		}
+	}
+}
+
+func TestSubrequestLoggerWithoutSubrequest(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	SubrequestLogger(context.Background()).Info("Plain")
+
+	if entry := findEntry(t, entries(), "Plain"); entry["subrequest_id"] != nil {
+		t.Errorf("entry = %v, want no subrequest fields", entry)
+	}
+}
+
+func TestContextFromFields(t *testing.T) {
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+	if got := ContextFromFields([]zap.Field{zap.String("key", "value"), contextField(ctx)}); got == nil || RequestIDFromContext(got) != "req-1" {
+		t.Fatalf("ContextFromFields() = %v, want the carried context", got)
+	}
+	if got := ContextFromFields([]zap.Field{zap.Any("ctx", ctx)}); got != nil {
+		t.Fatalf("ContextFromFields() = %v, want encoded fields ignored", got)
+	}
+
+	// Encoders skip the context
+	logger, buf := newTestLogger()
+	logger.Info("entry", contextField(ctx))
+	if entry := findEntry(t, buf.entries(t), "entry"); len(entry) != 4 {
+		t.Fatalf("entry = %v, want only the default keys", entry)
+	}
+}
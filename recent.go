@@ -0,0 +1,82 @@
+package ginlogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// recentLogBuffer is a bounded ring of encoded log entries. Once full, the
+// oldest entry is overwritten.
+type recentLogBuffer struct {
+	mu      sync.Mutex
+	entries []json.RawMessage
+	next    int
+	full    bool
+}
+
+func newRecentLogBuffer(capacity int) *recentLogBuffer {
+	return &recentLogBuffer{entries: make([]json.RawMessage, capacity)}
+}
+
+// Write stores one encoded entry; the JSON encoder writes each entry in a
+// single call
+func (b *recentLogBuffer) Write(p []byte) (int, error) {
+	entry := bytes.Clone(bytes.TrimSpace(p))
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	return len(p), nil
+}
+
+// Entries returns the buffered entries, oldest first
+func (b *recentLogBuffer) Entries() []json.RawMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]json.RawMessage(nil), b.entries[:b.next]...)
+	}
+	return append(append([]json.RawMessage(nil), b.entries[b.next:]...), b.entries[:b.next]...)
+}
+
+// recentLogs is shared by all StructuredLogger instances with RecentLogsCapacity set
+var recentLogs atomic.Pointer[recentLogBuffer]
+
+// recentLogsLogger returns a Logger writing to the shared buffer, replacing it
+// when its capacity differs
+func recentLogsLogger(capacity int) Logger {
+	buffer := recentLogs.Load()
+	if buffer == nil || len(buffer.entries) != capacity {
+		buffer = newRecentLogBuffer(capacity)
+		recentLogs.Store(buffer)
+	}
+
+	return NewZapLogger(zap.New(newWriterCore(zapcore.AddSync(buffer), zapcore.DebugLevel)))
+}
+
+// RecentLogsHandler returns a gin.HandlerFunc responding with the entries kept
+// by StructuredLoggerConfig.RecentLogsCapacity as a JSON array, oldest first.
+// Entries are not redacted beyond the middleware configuration, so only mount
+// it in development or on a protected (admin) route group.
+func RecentLogsHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries := []json.RawMessage{}
+		if buffer := recentLogs.Load(); buffer != nil {
+			entries = buffer.Entries()
+		}
+		c.JSON(http.StatusOK, entries)
+	}
+}
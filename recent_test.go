@@ -0,0 +1,48 @@
+package ginlogger
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecentLogBuffer(t *testing.T) {
+	buffer := newRecentLogBuffer(3)
+	for i := range 5 {
+		fmt.Fprintf(buffer, "{\"n\":%d}\n", i)
+	}
+
+	var got []string
+	for _, entry := range buffer.Entries() {
+		got = append(got, string(entry))
+	}
+	if fmt.Sprint(got) != `[{"n":2} {"n":3} {"n":4}]` {
+		t.Fatalf("entries = %v, want the last 3, oldest first", got)
+	}
+}
+
+func TestRecentLogsHandler(t *testing.T) {
+	t.Cleanup(func() { recentLogs.Store(nil) })
+
+	empty := serve(httptest.NewRequest(http.MethodGet, "/logs", nil), "/logs", RecentLogsHandler())
+	if empty.Body.String() != "[]" {
+		t.Errorf("body = %s, want an empty array before any entry", empty.Body.String())
+	}
+
+	logger, _ := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, RecentLogsCapacity: 2})
+	for _, path := range []string{"/a", "/b", "/c"} {
+		serve(httptest.NewRequest(http.MethodGet, path, nil), "/:path", ok, middleware)
+	}
+
+	w := serve(httptest.NewRequest(http.MethodGet, "/logs", nil), "/logs", RecentLogsHandler())
+	var entries []map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+		t.Fatalf("body is not a JSON array: %v\n%s", err, w.Body.String())
+	}
+	if len(entries) != 2 || entries[0]["path"] != "/b" || entries[1]["path"] != "/c" {
+		t.Errorf("entries = %v, want the last two requests", entries)
+	}
+}
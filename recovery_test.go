@@ -0,0 +1,85 @@
+package ginlogger
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// codedPanic is a custom panic value carrying an error code
+type codedPanic struct {
+	code    int
+	message string
+}
+
+func TestRecoveryLoggerPanicFormatter(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := RecoveryLoggerWithConfig(RecoveryLoggerConfig{
+		Logger: logger,
+		PanicFormatter: func(recovered any) string {
+			if p, ok := recovered.(codedPanic); ok {
+				return fmt.Sprintf("E%d: %s", p.code, p.message)
+			}
+			return fmt.Sprint(recovered)
+		},
+	})
+
+	recorder := serve(httptest.NewRequest(http.MethodGet, "/orders", nil), "/orders", func(c *gin.Context) {
+		panic(codedPanic{code: 42, message: "inventory out of sync"})
+	}, middleware)
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Errorf("status = %d, want 500", recorder.Code)
+	}
+	entry := findEntry(t, buf.entries(t), "Panic recovered")
+	if entry["panic"] != "E42: inventory out of sync" || entry["path"] != "/orders" || entry["level"] != "error" {
+		t.Errorf("entry = %v, want the formatted panic", entry)
+	}
+}
+
+func TestRecoveryLoggerDefaultFormat(t *testing.T) {
+	logger, buf := newTestLogger()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		panic("boom")
+	}, RecoveryLoggerWithConfig(RecoveryLoggerConfig{Logger: logger}))
+
+	if entry := findEntry(t, buf.entries(t), "Panic recovered"); entry["panic"] != "boom" {
+		t.Errorf("panic = %v, want the recovered value", entry["panic"])
+	}
+}
+
+// recordingReporter records the panics and errors it receives
+type recordingReporter struct {
+	panics []any
+	errors []error
+}
+
+func (r *recordingReporter) ReportPanic(c *gin.Context, recovered any) {
+	r.panics = append(r.panics, recovered)
+}
+
+func (r *recordingReporter) ReportError(c *gin.Context, err error) {
+	r.errors = append(r.errors, err)
+}
+
+func TestRecoveredPanicReportedOnce(t *testing.T) {
+	logger, _ := newTestLogger()
+	reporter := &recordingReporter{}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		panic("boom")
+	},
+		ErrorLoggerWithConfig(ErrorLoggerConfig{Logger: logger, Reporter: reporter}),
+		RecoveryLoggerWithConfig(RecoveryLoggerConfig{Logger: logger, Reporter: reporter}),
+	)
+
+	if len(reporter.panics) != 1 || reporter.panics[0] != "boom" {
+		t.Errorf("panics = %v, want the recovered panic", reporter.panics)
+	}
+	if len(reporter.errors) != 0 {
+		t.Errorf("errors = %v, want the panic not reported again as a 500", reporter.errors)
+	}
+}
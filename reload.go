@@ -0,0 +1,133 @@
+package ginlogger
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"syscall"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Environment variables read by ReloadFromEnv
+const (
+	EnvLogLevel   = "LOG_LEVEL"
+	EnvLogBodies  = "GIN_LOGGER_LOG_BODIES"
+	EnvSampleRate = "GIN_LOGGER_SAMPLE_RATE"
+)
+
+// runtimeConfig holds overrides applied on top of the middleware configuration.
+// Nil fields keep the configured behavior.
+type runtimeConfig struct {
+	level      *zapcore.Level
+	logBodies  *bool
+	sampleRate *float64
+}
+
+var runtimeOverrides atomic.Pointer[runtimeConfig]
+
+// ReloadFromEnv re-reads runtime overrides from the environment:
+//
+//   - LOG_LEVEL: minimum level of middleware entries and of loggers created
+//     by this package
+//   - GIN_LOGGER_LOG_BODIES: overrides StructuredLoggerConfig.LogRequestBody
+//   - GIN_LOGGER_SAMPLE_RATE: overrides StructuredLoggerConfig.SampleRate
+//     (-1 for NeverSample)
+//
+// Unset variables clear their override. On an invalid value an error is
+// returned and the previous overrides stay in effect.
+func ReloadFromEnv() error {
+	var config runtimeConfig
+
+	if value := os.Getenv(EnvLogLevel); value != "" {
+		level, err := zapcore.ParseLevel(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvLogLevel, err)
+		}
+		config.level = &level
+	}
+
+	if value := os.Getenv(EnvLogBodies); value != "" {
+		logBodies, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", EnvLogBodies, err)
+		}
+		config.logBodies = &logBodies
+	}
+
+	if value := os.Getenv(EnvSampleRate); value != "" {
+		sampleRate, err := strconv.ParseFloat(value, 64)
+		if err != nil || (sampleRate != NeverSample && (sampleRate < 0 || sampleRate > 1)) {
+			return fmt.Errorf("invalid %s: %q is not between 0 and 1 or -1", EnvSampleRate, value)
+		}
+		config.sampleRate = &sampleRate
+	}
+
+	runtimeOverrides.Store(&config)
+	return nil
+}
+
+// ReloadOnSIGHUP calls ReloadFromEnv whenever the process receives SIGHUP,
+// logging the outcome to logger (or the global logger when nil). The
+// returned function stops listening.
+func ReloadOnSIGHUP(logger Logger) (stop func()) {
+	logger = loggerOrGlobal(logger)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-signals:
+				if err := ReloadFromEnv(); err != nil {
+					logger.Error("Failed to reload configuration from environment", zap.Error(err))
+					continue
+				}
+				logger.Info("Configuration reloaded from environment")
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// overrideLevel returns the runtime minimum level, if set
+func overrideLevel() (zapcore.Level, bool) {
+	config := runtimeOverrides.Load()
+	if config == nil || config.level == nil {
+		return zapcore.InfoLevel, false
+	}
+	return *config.level, true
+}
+
+// overrideLogBodies returns the runtime body logging override or configured
+func overrideLogBodies(configured bool) bool {
+	config := runtimeOverrides.Load()
+	if config == nil || config.logBodies == nil {
+		return configured
+	}
+	return *config.logBodies
+}
+
+// overrideSampleRate returns the runtime sample rate override or configured
+func overrideSampleRate(configured float64) float64 {
+	config := runtimeOverrides.Load()
+	if config == nil || config.sampleRate == nil {
+		return configured
+	}
+	return *config.sampleRate
+}
@@ -0,0 +1,93 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestReloadFromEnvInvalidKeepsOverrides(t *testing.T) {
+	t.Cleanup(func() { runtimeOverrides.Store(nil) })
+
+	t.Setenv(EnvSampleRate, "0.5")
+	if err := ReloadFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	for env, value := range map[string]string{EnvLogLevel: "loud", EnvLogBodies: "maybe", EnvSampleRate: "2"} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if err := ReloadFromEnv(); err == nil || !strings.Contains(err.Error(), env) {
+				t.Fatalf("ReloadFromEnv() = %v, want an error naming %s", err, env)
+			}
+			if rate := overrideSampleRate(0); rate != 0.5 {
+				t.Errorf("sample rate override = %v, want the previous 0.5", rate)
+			}
+		})
+	}
+}
+
+func TestReloadFromEnvUnsetClearsOverrides(t *testing.T) {
+	t.Cleanup(func() { runtimeOverrides.Store(nil) })
+
+	t.Setenv(EnvLogBodies, "true")
+	if err := ReloadFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if !overrideLogBodies(false) {
+		t.Fatalf("body logging not overridden by %s=true", EnvLogBodies)
+	}
+
+	t.Setenv(EnvLogBodies, "")
+	if err := ReloadFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	if overrideLogBodies(false) {
+		t.Errorf("body logging override kept after unsetting %s", EnvLogBodies)
+	}
+}
+
+func TestLogBodiesEnvOverride(t *testing.T) {
+	t.Cleanup(func() { runtimeOverrides.Store(nil) })
+	t.Setenv(EnvLogBodies, "true")
+	if err := ReloadFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, MaxBodySize: 1024})
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("payload")), "/", ok, middleware)
+
+	if entry := buf.entries(t)[0]; entry["request_body"] != "payload" {
+		t.Errorf("entry = %v, want the body logged with %s=true", entry, EnvLogBodies)
+	}
+}
+
+func TestReloadOnSIGHUP(t *testing.T) {
+	t.Cleanup(func() { runtimeOverrides.Store(nil) })
+	t.Setenv(EnvLogLevel, "debug")
+
+	logger, buf := newTestLogger()
+	stop := ReloadOnSIGHUP(logger)
+	defer stop()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGHUP); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "Configuration reloaded") {
+		if time.Now().After(deadline) {
+			t.Fatal("configuration not reloaded on SIGHUP")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if level, ok := overrideLevel(); !ok || level != zapcore.DebugLevel {
+		t.Errorf("level override = %v, %v, want debug", level, ok)
+	}
+}
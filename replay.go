@@ -0,0 +1,96 @@
+package ginlogger
+
+import (
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// replayRedactedHeaders are always redacted in replays, as captures are often
+// shared to reproduce a failure
+var replayRedactedHeaders = map[string]bool{
+	"Authorization":       true,
+	"Cookie":              true,
+	"Proxy-Authorization": true,
+}
+
+// Body states of a replayed request
+const (
+	replayBodyComplete = iota
+	// replayBodyTruncated marks a body captured up to MaxBodySize only
+	replayBodyTruncated
+	// replayBodyOmitted marks a body that was not captured at all, e.g. one
+	// exceeding MaxBodySize or of unknown length
+	replayBodyOmitted
+)
+
+// replayCommand renders a request as a curl command that replays it. Headers
+// in redactHeaders (canonical keys) and replayRedactedHeaders are replaced by
+// [REDACTED]. An incomplete body is flagged in the comment line.
+func replayCommand(c *gin.Context, body string, bodyState int, redactHeaders map[string]bool) string {
+	r := c.Request
+
+	var b strings.Builder
+	b.WriteString("# " + time.Now().UTC().Format(time.RFC3339))
+	b.WriteString(" status=" + strconv.Itoa(c.Writer.Status()))
+	if requestID := c.GetString("request_id"); requestID != "" {
+		b.WriteString(" request_id=" + requestID)
+	}
+	switch bodyState {
+	case replayBodyTruncated:
+		b.WriteString(" body_truncated=true")
+	case replayBodyOmitted:
+		b.WriteString(" body_omitted=true")
+	}
+	b.WriteString("\n")
+
+	url := requestScheme(r) + "://" + r.Host + r.URL.RequestURI()
+	b.WriteString("curl -X " + shellQuote(r.Method) + " " + shellQuote(url))
+
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		// curl computes the length of the replayed body itself
+		if name != "Content-Length" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, value := range r.Header[name] {
+			if redactHeaders[name] || replayRedactedHeaders[name] {
+				value = redactedValue
+			}
+			b.WriteString(" \\\n  -H " + shellQuote(name+": "+value))
+		}
+	}
+
+	if body != "" {
+		b.WriteString(" \\\n  --data-binary " + shellQuote(body))
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// lockedWriter serializes writes of concurrent requests
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (w *lockedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.w.Write(p)
+}
@@ -0,0 +1,106 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func fail(c *gin.Context) {
+	c.Status(http.StatusInternalServerError)
+}
+
+func TestCaptureFailedRequestsFile(t *testing.T) {
+	dir := t.TempDir()
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:                logger,
+		CaptureFailedRequests: true,
+		FailedRequestDir:      dir,
+		RedactHeaders:         []string{"X-Api-Key"},
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "http://api.example.com/orders?id=1", strings.NewReader(`{"item":"it's"}`))
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("X-Api-Key", "secret-key")
+	req.Header.Set("Content-Type", "application/json")
+	serve(req, "/orders", fail, middleware)
+
+	file, _ := findEntry(t, buf.entries(t), "Server error")["failed_request_file"].(string)
+	if filepath.Dir(file) != dir {
+		t.Fatalf("failed_request_file = %q, want a file in %s", file, dir)
+	}
+
+	capture, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"status=500",
+		"curl -X 'POST' 'http://api.example.com/orders?id=1'",
+		"-H 'Authorization: [REDACTED]'",
+		"-H 'X-Api-Key: [REDACTED]'",
+		"-H 'Content-Type: application/json'",
+		`--data-binary '{"item":"it'\''s"}'`,
+	} {
+		if !strings.Contains(string(capture), want) {
+			t.Errorf("capture does not contain %q:\n%s", want, capture)
+		}
+	}
+	if strings.Contains(string(capture), "secret") || strings.Contains(string(capture), "body_") {
+		t.Errorf("capture leaks secrets or flags a complete body:\n%s", capture)
+	}
+}
+
+func TestCaptureFailedRequestsBodyState(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"omitted", strings.Repeat("x", 100), "body_omitted=true"},
+		{"no body", "", "status=500\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			capture := &syncBuffer{}
+			middleware := StructuredLogger(StructuredLoggerConfig{
+				Logger:                NewWriterLogger(&syncBuffer{}, "info"),
+				CaptureFailedRequests: true,
+				FailedRequestWriter:   capture,
+				MaxBodySize:           10,
+			})
+
+			serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body)), "/", fail, middleware)
+
+			if !strings.Contains(capture.String(), tt.want) {
+				t.Fatalf("capture does not contain %q:\n%s", tt.want, capture)
+			}
+		})
+	}
+}
+
+func TestCaptureFailedRequestsTruncated(t *testing.T) {
+	capture := &syncBuffer{}
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:                     NewWriterLogger(&syncBuffer{}, "info"),
+		CaptureFailedRequests:      true,
+		FailedRequestWriter:        capture,
+		MaxBodySize:                10,
+		CaptureUnknownLengthBodies: true,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 100)))
+	req.ContentLength = -1
+	serve(req, "/", fail, middleware)
+
+	if !strings.Contains(capture.String(), "body_truncated=true") || !strings.Contains(capture.String(), "'xxxxxxxxxx'") {
+		t.Fatalf("capture does not flag the truncated body:\n%s", capture)
+	}
+}
@@ -0,0 +1,273 @@
+package ginlogger
+
+import (
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap/zapcore"
+)
+
+// RequestInfo describes a completed request for StructuredLoggerConfig.OnComplete
+type RequestInfo struct {
+	Method string
+	// Route is the matched route pattern (e.g. "/users/:id"), empty when no
+	// route matched
+	Route     string
+	Path      string
+	Status    int
+	Latency   time.Duration
+	RequestID string
+	// RequestSize is the declared request body size, -1 when unknown
+	RequestSize int64
+	// ResponseSize is the number of response body bytes written
+	ResponseSize int
+	// Err is the last error attached to the context, if any
+	Err error
+}
+
+// newRequestInfo collects the RequestInfo of a completed request
+func newRequestInfo(c *gin.Context, latency time.Duration) RequestInfo {
+	info := RequestInfo{
+		Method:       c.Request.Method,
+		Route:        c.FullPath(),
+		Path:         c.Request.URL.Path,
+		Status:       c.Writer.Status(),
+		Latency:      latency,
+		RequestID:    c.GetString("request_id"),
+		RequestSize:  c.Request.ContentLength,
+		ResponseSize: max(responseSize(c), 0),
+	}
+
+	if last := c.Errors.Last(); last != nil {
+		info.Err = last.Err
+	}
+	return info
+}
+
+// requestScheme returns the scheme the client used, honoring X-Forwarded-Proto
+// set by a TLS-terminating proxy
+func requestScheme(r *http.Request) string {
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		// The header may contain a list when passing through several proxies
+		if i := strings.IndexByte(proto, ','); i >= 0 {
+			proto = proto[:i]
+		}
+		return strings.ToLower(strings.TrimSpace(proto))
+	}
+
+	if r.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// requestFingerprint returns a stable hash of the request shape: method, route
+// template, sorted header names and content type. Requests with the same shape
+// share a fingerprint regardless of parameter or header values.
+func requestFingerprint(r *http.Request, route string) string {
+	if route == "" {
+		route = r.URL.Path
+	}
+
+	names := make([]string, 0, len(r.Header))
+	for name := range r.Header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	hash := fnv.New64a()
+	hash.Write([]byte(r.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(route))
+	hash.Write([]byte{0})
+	hash.Write([]byte(strings.Join(names, ",")))
+	hash.Write([]byte{0})
+	hash.Write([]byte(r.Header.Get("Content-Type")))
+
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
+
+// truncateURL truncates path and query so that together they are at most
+// maxLength long, reporting whether anything was cut
+func truncateURL(path, query string, maxLength int) (string, string, bool) {
+	if len(path)+len(query) <= maxLength {
+		return path, query, false
+	}
+
+	if len(path) >= maxLength {
+		return truncateUTF8(path, maxLength), "", true
+	}
+	return path, truncateUTF8(query, maxLength-len(path)), true
+}
+
+// truncateUTF8 returns at most the first n bytes of s, backing off to a rune
+// boundary so that multi-byte characters are never split
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// refererHost returns the host of the Referer header, or "" when it is
+// missing or not an absolute URL
+func refererHost(r *http.Request) string {
+	referer := r.Referer()
+	if referer == "" {
+		return ""
+	}
+
+	u, err := url.Parse(referer)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// routeParams encodes matched path parameters as an object
+type routeParams struct {
+	params gin.Params
+	redact map[string]bool
+}
+
+func (p routeParams) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	for _, param := range p.params {
+		if p.redact[param.Key] {
+			encoder.AddString(param.Key, redactedValue)
+		} else {
+			encoder.AddString(param.Key, param.Value)
+		}
+	}
+	return nil
+}
+
+// serverTimingMetric is a Server-Timing metric with a duration
+type serverTimingMetric struct {
+	name     string
+	duration float64
+}
+
+// serverTiming encodes Server-Timing metrics as an object of durations in
+// milliseconds, e.g. {"db": 40, "cache": 5}
+type serverTiming []serverTimingMetric
+
+func (t serverTiming) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	for _, metric := range t {
+		encoder.AddFloat64(metric.name, metric.duration)
+	}
+	return nil
+}
+
+// parseServerTiming parses Server-Timing header values such as
+// `db;dur=40, cache;desc="hit";dur=5`. Metrics without a valid dur are skipped.
+func parseServerTiming(values []string) serverTiming {
+	var timing serverTiming
+	for _, value := range values {
+		for _, entry := range splitUnquoted(value, ',') {
+			params := splitUnquoted(entry, ';')
+			name := strings.TrimSpace(params[0])
+			if name == "" {
+				continue
+			}
+
+			for _, param := range params[1:] {
+				key, raw, ok := strings.Cut(param, "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "dur") {
+					continue
+				}
+				if duration, err := strconv.ParseFloat(strings.Trim(strings.TrimSpace(raw), `"`), 64); err == nil {
+					timing = append(timing, serverTimingMetric{name: name, duration: duration})
+				}
+				break
+			}
+		}
+	}
+	return timing
+}
+
+// splitUnquoted splits s at sep, ignoring separators inside double quotes
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	quoted := false
+	begin := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				parts = append(parts, s[begin:i])
+				begin = i + 1
+			}
+		}
+	}
+	return append(parts, s[begin:])
+}
+
+// queryParams encodes selected query parameters as an object, joining
+// repeated values with commas
+type queryParams struct {
+	values url.Values
+	names  []string
+}
+
+// allowedQueryParams returns the allowlisted parameters present in values
+func allowedQueryParams(values url.Values, allowlist []string) (queryParams, bool) {
+	var names []string
+	for _, name := range allowlist {
+		if values.Has(name) {
+			names = append(names, name)
+		}
+	}
+	return queryParams{values: values, names: names}, len(names) > 0
+}
+
+func (p queryParams) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	for _, name := range p.names {
+		encoder.AddString(name, strings.Join(p.values[name], ","))
+	}
+	return nil
+}
+
+// parseClientTime parses a client-claimed time header as an HTTP date,
+// RFC 3339 timestamp or Unix seconds
+func parseClientTime(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		return t, true
+	}
+
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, true
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil && seconds > 0 {
+		return time.Unix(seconds, 0), true
+	}
+
+	return time.Time{}, false
+}
+
+// responseSize returns the number of response body bytes written. HEAD
+// responses never carry a body, even if the handler wrote one (net/http
+// discards it), so they are always 0.
+func responseSize(c *gin.Context) int {
+	if c.Request.Method == http.MethodHead {
+		return 0
+	}
+	return c.Writer.Size()
+}
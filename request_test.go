@@ -0,0 +1,424 @@
+package ginlogger
+
+import (
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestScheme(t *testing.T) {
+	tests := []struct {
+		name      string
+		forwarded string
+		tls       bool
+		want      string
+	}{
+		{"plain", "", false, "http"},
+		{"tls", "", true, "https"},
+		{"forwarded", "HTTPS", false, "https"},
+		{"forwarded list", "https, http", false, "https"},
+		{"forwarded wins over tls", "http", true, "http"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-Proto", tt.forwarded)
+			}
+			if tt.tls {
+				req.TLS = &tls.ConnectionState{}
+			}
+
+			if got := requestScheme(req); got != tt.want {
+				t.Fatalf("requestScheme() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogSchemeAndHost(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogScheme: true, LogHost: true})
+
+	req := httptest.NewRequest(http.MethodGet, "https://api.example.com/users", nil)
+	serve(req, "/users", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	if entry["scheme"] != "https" || entry["host"] != "api.example.com" {
+		t.Fatalf("entry = %v, want scheme https and host api.example.com", entry)
+	}
+}
+
+func TestRequestFingerprint(t *testing.T) {
+	request := func(path, auth, contentType string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, nil)
+		req.Header.Set("Authorization", auth)
+		req.Header.Set("Content-Type", contentType)
+		return req
+	}
+
+	base := requestFingerprint(request("/users/1", "Bearer a", "application/json"), "/users/:id")
+	if got := requestFingerprint(request("/users/2", "Bearer b", "application/json"), "/users/:id"); got != base {
+		t.Errorf("fingerprint differs for the same shape: %s and %s", got, base)
+	}
+	if got := requestFingerprint(request("/users/1", "Bearer a", "text/plain"), "/users/:id"); got == base {
+		t.Error("fingerprint ignores the content type")
+	}
+
+	extraHeader := request("/users/1", "Bearer a", "application/json")
+	extraHeader.Header.Set("X-Debug", "1")
+	if got := requestFingerprint(extraHeader, "/users/:id"); got == base {
+		t.Error("fingerprint ignores header names")
+	}
+}
+
+func TestLogFingerprint(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogFingerprint: true})
+
+	serve(httptest.NewRequest(http.MethodGet, "/users/1", nil), "/users/:id", ok, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/users/2", nil), "/users/:id", ok, middleware)
+
+	entries := buf.entries(t)
+	if entries[0]["request_fingerprint"] == nil || entries[0]["request_fingerprint"] != entries[1]["request_fingerprint"] {
+		t.Fatalf("entries = %v, want the same fingerprint for both requests", entries)
+	}
+}
+
+func TestTruncateURL(t *testing.T) {
+	tests := []struct {
+		name, path, query string
+		max               int
+		wantPath          string
+		wantQuery         string
+		truncated         bool
+	}{
+		{"short", "/a", "b=1", 10, "/a", "b=1", false},
+		{"query cut", "/search", "q=abcdefgh", 10, "/search", "q=a", true},
+		{"path cut", "/very/long/path", "q=1", 5, "/very", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, query, truncated := truncateURL(tt.path, tt.query, tt.max)
+			if path != tt.wantPath || query != tt.wantQuery || truncated != tt.truncated {
+				t.Fatalf("truncateURL() = %q, %q, %v, want %q, %q, %v", path, query, truncated, tt.wantPath, tt.wantQuery, tt.truncated)
+			}
+		})
+	}
+}
+
+func TestMaxURLLength(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, MaxURLLength: 16})
+
+	serve(httptest.NewRequest(http.MethodGet, "/search?q="+strings.Repeat("x", 1000), nil), "/search", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	if entry["url_truncated"] != true || entry["path"] != "/search" || entry["query"] != "q=xxxxxxx" {
+		t.Fatalf("entry = %v, want the query truncated to 16 bytes in total", entry)
+	}
+}
+
+func TestSecurityLoggerOversizedURL(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := SecurityLoggerWithConfig(SecurityLoggerConfig{Logger: logger, MaxURLLength: 32})
+
+	serve(httptest.NewRequest(http.MethodGet, "/"+strings.Repeat("a", 100), nil), "/*path", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Suspicious request detected")
+	if entry["reason"] != "Oversized URL" || entry["url_truncated"] != true || len(entry["path"].(string)) != 32 {
+		t.Fatalf("entry = %v, want an oversized URL warning with a 32 byte path", entry)
+	}
+}
+
+func TestRefererHost(t *testing.T) {
+	tests := []struct {
+		referer string
+		want    string
+	}{
+		{"", ""},
+		{"https://shop.example.com/cart?token=secret", "shop.example.com"},
+		{"http://localhost:8080/", "localhost:8080"},
+		{"/relative/path", ""},
+		{"%zz", ""},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.referer != "" {
+			req.Header.Set("Referer", tt.referer)
+		}
+		if got := refererHost(req); got != tt.want {
+			t.Errorf("refererHost(%q) = %q, want %q", tt.referer, got, tt.want)
+		}
+	}
+}
+
+func TestLogRefererHost(t *testing.T) {
+	logger, buf := newTestLogger()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Referer", "https://shop.example.com/cart?token=secret")
+	serve(req, "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRefererHost: true}))
+
+	entry := buf.entries(t)[0]
+	if entry["referer_host"] != "shop.example.com" {
+		t.Errorf("referer_host = %v, want shop.example.com", entry["referer_host"])
+	}
+	if strings.Contains(buf.String(), "token=secret") {
+		t.Errorf("referer path or query logged: %s", buf.String())
+	}
+}
+
+func TestLogRouteParams(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:            logger,
+		LogRouteParams:    true,
+		RedactRouteParams: []string{"token"},
+	})
+
+	serve(httptest.NewRequest(http.MethodGet, "/users/123/invites/abc", nil), "/users/:id/invites/:token", ok, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/health", nil), "/health", ok, middleware)
+
+	entries := buf.entries(t)
+	params, _ := entries[0]["route_params"].(map[string]any)
+	if params["id"] != "123" || params["token"] != redactedValue {
+		t.Errorf("route_params = %v, want id and the redacted token", params)
+	}
+	if _, ok := entries[1]["route_params"]; ok {
+		t.Errorf("route_params logged for a route without parameters: %v", entries[1])
+	}
+}
+
+func TestLogQueryParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		config StructuredLoggerConfig
+		query  any
+		params map[string]any
+	}{
+		{"raw query", StructuredLoggerConfig{}, "page=2&token=secret&sort=name&sort=-date", nil},
+		{"allowlist", StructuredLoggerConfig{LogQueryParams: []string{"page", "sort", "missing"}}, nil, map[string]any{"page": "2", "sort": "name,-date"}},
+		{"omitted", StructuredLoggerConfig{OmitQuery: true}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			tt.config.Logger = logger
+			serve(httptest.NewRequest(http.MethodGet, "/search?page=2&token=secret&sort=name&sort=-date", nil), "/search", ok, StructuredLogger(tt.config))
+
+			entry := buf.entries(t)[0]
+			if tt.query != nil && entry["query"] != tt.query {
+				t.Errorf("query = %v, want %v", entry["query"], tt.query)
+			}
+			if tt.query == nil {
+				if _, ok := entry["query"]; ok {
+					t.Errorf("raw query logged: %v", entry["query"])
+				}
+				if strings.Contains(buf.String(), "secret") {
+					t.Errorf("unlisted parameter logged: %s", buf.String())
+				}
+			}
+
+			params, _ := entry["query_params"].(map[string]any)
+			if len(params) != len(tt.params) {
+				t.Fatalf("query_params = %v, want %v", params, tt.params)
+			}
+			for name, value := range tt.params {
+				if params[name] != value {
+					t.Errorf("query_params[%s] = %v, want %v", name, params[name], value)
+				}
+			}
+		})
+	}
+}
+
+func TestParseClientTime(t *testing.T) {
+	want := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		ok    bool
+	}{
+		{"Fri, 16 Oct 2026 12:00:00 GMT", true},
+		{"2026-10-16T14:00:00+02:00", true},
+		{" 1792152000 ", true},
+		{"", false},
+		{"yesterday", false},
+		{"-5", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseClientTime(tt.value)
+		if ok != tt.ok || (ok && !got.Equal(want)) {
+			t.Errorf("parseClientTime(%q) = %v, %v, want %v, %v", tt.value, got, ok, want, tt.ok)
+		}
+	}
+}
+
+func TestLogClockSkew(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogClockSkew: true, ClockSkewHeader: "X-Client-Time"})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Client-Time", time.Now().Add(-time.Minute).UTC().Format(time.RFC3339))
+	serve(req, "/", ok, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+
+	entries := buf.entries(t)
+	// The RFC 3339 value has second precision
+	if skew, _ := entries[0]["clock_skew"].(float64); skew < 59 || skew > 62 {
+		t.Errorf("clock_skew = %v, want about 60s", entries[0]["clock_skew"])
+	}
+	if _, ok := entries[1]["clock_skew"]; ok {
+		t.Errorf("clock_skew logged without a client time: %v", entries[1])
+	}
+}
+
+func TestHeadRequests(t *testing.T) {
+	logger, buf := newTestLogger()
+	page := func(c *gin.Context) { c.String(http.StatusOK, "page body") }
+
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+	r.GET("/", page)
+	r.HEAD("/", page)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodHead, "/", nil))
+
+	entries := buf.entries(t)
+	if entries[0]["body_size"] != float64(len("page body")) || entries[1]["body_size"] != float64(0) {
+		t.Errorf("body_size = %v for GET and %v for HEAD, want 9 and 0", entries[0]["body_size"], entries[1]["body_size"])
+	}
+
+	skipping, skipped := newTestLogger()
+	serve(httptest.NewRequest(http.MethodHead, "/", nil), "/", page, StructuredLogger(StructuredLoggerConfig{Logger: skipping, SkipHead: true}))
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", page, StructuredLogger(StructuredLoggerConfig{Logger: skipping, SkipHead: true}))
+	if entries := skipped.entries(t); len(entries) != 1 || entries[0]["method"] != http.MethodGet {
+		t.Errorf("entries = %v, want only the GET request with SkipHead", entries)
+	}
+}
+
+func TestOnComplete(t *testing.T) {
+	var infos []RequestInfo
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:     logger,
+		SampleRate: NeverSample,
+		SkipPaths:  []string{"/health"},
+		OnComplete: func(info RequestInfo) {
+			// Runs after the entry was written
+			infos = append(infos, info)
+			if info.Status >= 400 && !strings.Contains(buf.String(), info.Path) {
+				t.Errorf("OnComplete called before %s was logged", info.Path)
+			}
+		},
+	})
+
+	r := gin.New()
+	r.Use(RequestIDMiddleware(), middleware)
+	r.GET("/health", ok)
+	r.GET("/users/:id", ok)
+	r.POST("/orders", func(c *gin.Context) {
+		c.Error(errors.New("out of stock"))
+		c.String(http.StatusConflict, "conflict")
+	})
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/7", nil))
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"sku":1}`))
+	req.Header.Set("X-Request-ID", "req-9")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if len(infos) != 2 {
+		t.Fatalf("OnComplete called %d times, want for sampled out and logged requests but not skipped ones", len(infos))
+	}
+	if infos[0].Route != "/users/:id" || infos[0].Path != "/users/7" || infos[0].Status != http.StatusOK {
+		t.Errorf("info = %+v, want the sampled out GET /users/7", infos[0])
+	}
+	got := infos[1]
+	if got.Method != http.MethodPost || got.Status != http.StatusConflict || got.RequestID != "req-9" ||
+		got.RequestSize != 9 || got.ResponseSize != len("conflict") || got.Err == nil || got.Err.Error() != "out of stock" || got.Latency <= 0 {
+		t.Errorf("info = %+v, want the failed POST /orders", got)
+	}
+}
+
+func TestDetectRetries(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, DetectRetries: true, RetryWindow: 50 * time.Millisecond})
+	request := func(header, value string) {
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		serve(req, "/", ok, middleware)
+	}
+
+	request("Idempotency-Key", "order-1")
+	request("Idempotency-Key", "order-1")
+	request("X-Retry-Count", "2")
+	request("X-Retry-Count", "0")
+	request("", "")
+	time.Sleep(60 * time.Millisecond)
+	request("Idempotency-Key", "order-1")
+
+	want := []any{nil, true, true, nil, nil, nil}
+	entries := buf.entries(t)
+	if len(entries) != len(want) {
+		t.Fatalf("got %d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if entry["is_retry"] != want[i] {
+			t.Errorf("request %d: is_retry = %v, want %v", i, entry["is_retry"], want[i])
+		}
+	}
+}
+
+func TestParseServerTiming(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		want   serverTiming
+	}{
+		{"simple", []string{"db;dur=40, cache;dur=5"}, serverTiming{{"db", 40}, {"cache", 5}}},
+		{"quoted description", []string{`cache;desc="hit; warm, fast";dur=2.5`}, serverTiming{{"cache", 2.5}}},
+		{"multiple headers", []string{"db;dur=40", "render;dur=12"}, serverTiming{{"db", 40}, {"render", 12}}},
+		{"without dur", []string{"miss, db;dur=abc, ;dur=3"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseServerTiming(tt.values); !slices.Equal(got, tt.want) {
+				t.Fatalf("parseServerTiming(%q) = %v, want %v", tt.values, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogServerTiming(t *testing.T) {
+	logger, buf := newTestLogger()
+	timed := func(c *gin.Context) {
+		c.Header("Server-Timing", `db;dur=40, cache;desc="hit";dur=5`)
+		c.Status(http.StatusOK)
+	}
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", timed, StructuredLogger(StructuredLoggerConfig{Logger: logger, LogServerTiming: true}))
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", timed, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	entries := buf.entries(t)
+	timing, _ := entries[0]["server_timing"].(map[string]any)
+	if timing["db"] != float64(40) || timing["cache"] != float64(5) {
+		t.Errorf("server_timing = %v, want the handler's metrics in milliseconds", entries[0]["server_timing"])
+	}
+	if _, ok := entries[1]["server_timing"]; ok {
+		t.Errorf("server_timing logged without LogServerTiming: %v", entries[1])
+	}
+}
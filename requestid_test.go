@@ -0,0 +1,92 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRequestIDDuplicateDetection(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := RequestIDMiddlewareWithConfig(RequestIDConfig{Logger: logger, DetectDuplicates: true})
+
+	for _, id := range []string{"req-1", "req-2", "req-1"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Request-ID", id)
+		if w := serve(req, "/", ok, middleware); w.Header().Get("X-Request-ID") != id {
+			t.Fatalf("X-Request-ID = %q, want the incoming %q", w.Header().Get("X-Request-ID"), id)
+		}
+	}
+
+	entries := buf.entries(t)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v, want a single duplicate warning", entries)
+	}
+	warning := findEntry(t, entries, "Duplicate request ID detected")
+	if warning["request_id"] != "req-1" || warning["duplicate_request_id"] != true {
+		t.Fatalf("warning = %v, want req-1 flagged as duplicate", warning)
+	}
+}
+
+func TestRequestIDGeneratedIsNotDuplicate(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := RequestIDMiddlewareWithConfig(RequestIDConfig{Logger: logger, DetectDuplicates: true})
+
+	for range 3 {
+		if w := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware); w.Header().Get("X-Request-ID") == "" {
+			t.Fatal("no X-Request-ID generated")
+		}
+	}
+	if entries := buf.entries(t); len(entries) != 0 {
+		t.Fatalf("entries = %v, want none for generated IDs", entries)
+	}
+}
+
+func TestSeenCache(t *testing.T) {
+	cache := newSeenCache(2, time.Minute)
+	now := time.Now()
+
+	if cache.Seen("a", now) {
+		t.Fatal("first sighting reported as duplicate")
+	}
+	if !cache.Seen("a", now.Add(time.Second)) {
+		t.Fatal("repeat within the window not reported")
+	}
+	if cache.Seen("a", now.Add(2*time.Minute)) {
+		t.Fatal("repeat after the window reported")
+	}
+
+	// "a" is evicted as the least recently seen key
+	cache.Seen("b", now)
+	cache.Seen("c", now)
+	if cache.Seen("a", now.Add(2*time.Minute)) {
+		t.Fatal("evicted key reported as duplicate")
+	}
+}
+
+func TestSetRequestIDGenerator(t *testing.T) {
+	restore := SetRequestIDGenerator(SequentialRequestIDs("test"))
+	middleware := RequestIDMiddleware()
+
+	for _, want := range []string{"test-1", "test-2"} {
+		if got := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware).Header().Get("X-Request-ID"); got != want {
+			t.Errorf("X-Request-ID = %q, want %q", got, want)
+		}
+	}
+
+	// Nested overrides restore the previous generator
+	restoreInner := SetRequestIDGenerator(func() string { return "fixed" })
+	if got := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware).Header().Get("X-Request-ID"); got != "fixed" {
+		t.Errorf("X-Request-ID = %q, want fixed", got)
+	}
+	restoreInner()
+	if got := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware).Header().Get("X-Request-ID"); got != "test-3" {
+		t.Errorf("X-Request-ID = %q after restoring, want test-3", got)
+	}
+
+	restore()
+	if got := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware).Header().Get("X-Request-ID"); got == "" || got == "test-4" {
+		t.Errorf("X-Request-ID = %q, want the default generator restored", got)
+	}
+}
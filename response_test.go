@@ -0,0 +1,100 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLogResponseBody(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogResponseBody: true, MaxBodySize: 5})
+
+	recorder := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		c.String(http.StatusOK, "hello world")
+	}, middleware)
+
+	if recorder.Body.String() != "hello world" {
+		t.Errorf("client received %q, want the full body", recorder.Body.String())
+	}
+	entry := buf.entries(t)[0]
+	if entry["response_body"] != "hello" || entry["response_body_truncated"] != true {
+		t.Fatalf("entry = %v, want the body truncated to MaxBodySize", entry)
+	}
+}
+
+func TestCaptureResponseOnError(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, CaptureResponseOnError: true, MaxBodySize: 1024})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		c.String(http.StatusOK, "fine")
+	}, middleware)
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		c.String(http.StatusBadGateway, "upstream down")
+	}, middleware)
+
+	entries := buf.entries(t)
+	if _, ok := entries[0]["response_body"]; ok {
+		t.Errorf("successful response body captured: %v", entries[0])
+	}
+	if entries[1]["response_body"] != "upstream down" {
+		t.Errorf("entry = %v, want the 5xx response body", entries[1])
+	}
+}
+
+func TestSetResponseTimeHeader(t *testing.T) {
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: NewWriterLogger(&syncBuffer{}, "info"), SetResponseTimeHeader: true})
+	handlers := map[string]gin.HandlerFunc{
+		"body":  func(c *gin.Context) { c.String(http.StatusOK, "body") },
+		"empty": ok,
+	}
+
+	for name, handler := range handlers {
+		t.Run(name, func(t *testing.T) {
+			w := serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", handler, middleware)
+			value := w.Header().Get("X-Response-Time")
+			if _, err := time.ParseDuration(value); err != nil {
+				t.Errorf("X-Response-Time = %q, want a duration", value)
+			}
+		})
+	}
+}
+
+func TestWarnNoResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler gin.HandlerFunc
+		warn    bool
+	}{
+		{"nothing written", func(c *gin.Context) {}, true},
+		{"status only", func(c *gin.Context) { c.Status(http.StatusNoContent) }, false},
+		{"aborted with status", func(c *gin.Context) { c.AbortWithStatus(http.StatusNotModified) }, false},
+		{"body written", func(c *gin.Context) { c.String(http.StatusOK, "hello") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger, buf := newTestLogger()
+			middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, WarnNoResponse: true})
+			serve(httptest.NewRequest(http.MethodGet, "/users/1", nil), "/users/:id", tt.handler, middleware)
+
+			var warning map[string]any
+			for _, entry := range buf.entries(t) {
+				if entry["msg"] == "No response written" {
+					warning = entry
+				}
+			}
+
+			if (warning != nil) != tt.warn {
+				t.Fatalf("warning logged = %v, want %v: %s", warning != nil, tt.warn, buf)
+			}
+			if tt.warn && (warning["no_response_written"] != true || warning["route"] != "/users/:id") {
+				t.Fatalf("warning = %v, want no_response_written and route", warning)
+			}
+		})
+	}
+}
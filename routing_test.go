@@ -0,0 +1,37 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNoRouteAndNoMethodLogger(t *testing.T) {
+	entries := useFileGlobalLogger(t)
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.NoRoute(NoRouteLogger())
+	r.NoMethod(NoMethodLogger())
+	r.GET("/users", ok)
+
+	missing := httptest.NewRecorder()
+	r.ServeHTTP(missing, httptest.NewRequest(http.MethodGet, "/unknown", nil))
+	wrongMethod := httptest.NewRecorder()
+	r.ServeHTTP(wrongMethod, httptest.NewRequest(http.MethodDelete, "/users", nil))
+
+	if missing.Code != http.StatusNotFound || wrongMethod.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d and %d, want 404 and 405", missing.Code, wrongMethod.Code)
+	}
+
+	logged := entries()
+	notFound := findEntry(t, logged, "Route not found")
+	if notFound["event"] != "route_not_found" || notFound["path"] != "/unknown" || notFound["status"] != float64(404) || notFound["level"] != "warn" {
+		t.Errorf("entry = %v, want a 404 warning for /unknown", notFound)
+	}
+	notAllowed := findEntry(t, logged, "Method not allowed")
+	if notAllowed["event"] != "method_not_allowed" || notAllowed["method"] != "DELETE" || notAllowed["status"] != float64(405) {
+		t.Errorf("entry = %v, want a 405 warning for DELETE", notAllowed)
+	}
+}
@@ -0,0 +1,59 @@
+package ginlogger
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NeverSample is a sample rate that keeps nothing, e.g. to silence the
+// successful requests of a health check route through PerRouteSampleRate.
+// Sample rates are the fraction kept; the zero rate disables sampling so
+// that rates left unset keep everything.
+const NeverSample = -1.0
+
+// sampled reports whether a random draw falls within rate (see NeverSample)
+func sampled(rate float64) bool {
+	if rate == 0 || rate >= 1 {
+		return true
+	}
+	return rate > 0 && rand.Float64() < rate
+}
+
+// sampleRequest reports whether a successful request is logged at rate (see
+// NeverSample). When keyFunc returns a non-empty key the decision is derived
+// from its hash, so all requests sharing a key are either logged or dropped
+// together; a positive window rotates the decision every window so the same
+// keys are not dropped forever.
+func sampleRequest(c *gin.Context, rate float64, keyFunc func(*gin.Context) string, window time.Duration) bool {
+	if rate == 0 || rate >= 1 {
+		return true
+	}
+	if rate < 0 {
+		return false
+	}
+
+	if keyFunc != nil {
+		if key := keyFunc(c); key != "" {
+			return sampleKey(key, rate, window, time.Now())
+		}
+	}
+
+	return rand.Float64() < rate
+}
+
+// sampleKey maps the hash of key (salted with the current window) onto
+// [0, 1) and keeps it when it falls below rate
+func sampleKey(key string, rate float64, window time.Duration, now time.Time) bool {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	if window > 0 {
+		h.Write([]byte(strconv.FormatInt(now.UnixNano()/int64(window), 10)))
+	}
+
+	return float64(h.Sum64())/math.MaxUint64 < rate
+}
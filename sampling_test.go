@@ -0,0 +1,209 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// countLogged runs n requests to path through an engine using config and
+// returns the number of entries logged
+func countLogged(t *testing.T, config StructuredLoggerConfig, routes []string, path string, status int, n int) int {
+	t.Helper()
+
+	logger, buf := newTestLogger()
+	config.Logger = logger
+
+	r := gin.New()
+	r.Use(StructuredLogger(config))
+	for _, route := range routes {
+		r.GET(route, func(c *gin.Context) { c.Status(status) })
+	}
+
+	for range n {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	return len(buf.entries(t))
+}
+
+func TestPerRouteSampleRate(t *testing.T) {
+	const n = 2000
+	config := StructuredLoggerConfig{
+		SampleRate: 0.5,
+		PerRouteSampleRate: map[string]float64{
+			"/events/:id": 0.1,
+			"/checkout":   1,
+		},
+	}
+	routes := []string{"/events/:id", "/checkout", "/other"}
+
+	if got := countLogged(t, config, routes, "/events/1", http.StatusOK, n); got < n*5/100 || got > n*15/100 {
+		t.Errorf("/events/:id logged %d of %d, want about 10%%", got, n)
+	}
+	if got := countLogged(t, config, routes, "/checkout", http.StatusOK, n); got != n {
+		t.Errorf("/checkout logged %d of %d, want all", got, n)
+	}
+	if got := countLogged(t, config, routes, "/other", http.StatusOK, n); got < n*40/100 || got > n*60/100 {
+		t.Errorf("/other logged %d of %d, want about 50%%", got, n)
+	}
+	// Errors bypass sampling
+	if got := countLogged(t, config, routes, "/events/1", http.StatusInternalServerError, 100); got != 100 {
+		t.Errorf("/events/:id errors logged %d of 100, want all", got)
+	}
+}
+
+func TestSampleKeyFuncConsistent(t *testing.T) {
+	config := StructuredLoggerConfig{
+		SampleRate:    0.5,
+		SampleKeyFunc: func(c *gin.Context) string { return c.Query("user") },
+	}
+
+	// All requests of a user are either logged or dropped together
+	for _, user := range []string{"alice", "bob", "carol", "dave"} {
+		got := countLogged(t, config, []string{"/"}, "/?user="+user, http.StatusOK, 20)
+		if got != 0 && got != 20 {
+			t.Errorf("user %s logged %d of 20, want all or none", user, got)
+		}
+	}
+}
+
+func TestLogWhenResponseHeader(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:                logger,
+		SampleRate:            NeverSample,
+		LogRequestBody:        true,
+		BodySampleRate:        NeverSample,
+		MaxBodySize:           1024,
+		LogHeaders:            []string{"X-Debug"},
+		VerboseFields:         []string{"header_*"},
+		DiagnosticSampleRate:  NeverSample,
+		LogWhenResponseHeader: "X-Debug-Log",
+	})
+
+	r := gin.New()
+	r.Use(middleware)
+	r.POST("/plain", ok)
+	r.POST("/forced", func(c *gin.Context) {
+		c.Header("X-Debug-Log", "1")
+		c.Status(http.StatusOK)
+	})
+	for _, path := range []string{"/plain", "/forced"} {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader("payload"))
+		req.Header.Set("X-Debug", "on")
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	entries := buf.entries(t)
+	if len(entries) != 1 || entries[0]["path"] != "/forced" {
+		t.Fatalf("entries = %v, want only the forced request", entries)
+	}
+	if entries[0]["request_body"] != "payload" || entries[0]["header_X-Debug"] != "on" {
+		t.Errorf("entry = %v, want the body and verbose fields despite sampling", entries[0])
+	}
+}
+
+func TestSampleRateSemantics(t *testing.T) {
+	routes := []string{"/", "/health"}
+
+	tests := []struct {
+		name   string
+		config StructuredLoggerConfig
+		path   string
+		want   int
+	}{
+		{"unset logs all", StructuredLoggerConfig{}, "/", 100},
+		{"one logs all", StructuredLoggerConfig{SampleRate: 1}, "/", 100},
+		{"never logs none", StructuredLoggerConfig{SampleRate: NeverSample}, "/", 0},
+		{"zero route rate logs all", StructuredLoggerConfig{SampleRate: NeverSample, PerRouteSampleRate: map[string]float64{"/health": 0}}, "/health", 100},
+		{"never route rate logs none", StructuredLoggerConfig{PerRouteSampleRate: map[string]float64{"/health": NeverSample}}, "/health", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countLogged(t, tt.config, routes, tt.path, http.StatusOK, 100); got != tt.want {
+				t.Errorf("logged %d of 100, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// Errors bypass sampling
+	config := StructuredLoggerConfig{PerRouteSampleRate: map[string]float64{"/health": NeverSample}}
+	if got := countLogged(t, config, routes, "/health", http.StatusInternalServerError, 100); got != 100 {
+		t.Errorf("errors logged %d of 100, want all", got)
+	}
+}
+
+func TestSampleRateEnvOverride(t *testing.T) {
+	t.Setenv(EnvSampleRate, "-1")
+	if err := ReloadFromEnv(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		runtimeOverrides.Store(nil)
+	})
+
+	if got := countLogged(t, StructuredLoggerConfig{}, []string{"/"}, "/", http.StatusOK, 50); got != 0 {
+		t.Errorf("logged %d of 50 with %s=-1, want none", got, EnvSampleRate)
+	}
+}
+
+func TestDiagnosticSampleRate(t *testing.T) {
+	const n = 1000
+	for _, tt := range []struct {
+		rate     float64
+		min, max int
+	}{{0, n, n}, {0.3, n * 20 / 100, n * 40 / 100}, {NeverSample, 0, 0}} {
+		logger, buf := newTestLogger()
+		middleware := StructuredLogger(StructuredLoggerConfig{
+			Logger:               logger,
+			LogHeaders:           []string{"X-Debug"},
+			VerboseFields:        []string{"header_*"},
+			DiagnosticSampleRate: tt.rate,
+		})
+
+		r := gin.New()
+		r.Use(middleware)
+		r.GET("/", ok)
+		for range n {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set("X-Debug", "on")
+			r.ServeHTTP(httptest.NewRecorder(), req)
+		}
+
+		verbose := 0
+		for _, entry := range buf.entries(t) {
+			if entry["status"] == nil || entry["method"] == nil {
+				t.Fatalf("core fields missing: %v", entry)
+			}
+			if _, ok := entry["header_X-Debug"]; ok {
+				verbose++
+			}
+		}
+		if verbose < tt.min || verbose > tt.max {
+			t.Errorf("DiagnosticSampleRate %v: verbose fields in %d of %d entries, want %d to %d", tt.rate, verbose, n, tt.min, tt.max)
+		}
+	}
+}
+
+func TestValidateSampleRates(t *testing.T) {
+	config := StructuredLoggerConfig{
+		SampleRate:         1.5,
+		PerRouteSampleRate: map[string]float64{"/": -0.5},
+	}
+	if err := config.Validate(); err == nil {
+		t.Fatal("Validate() accepted out of range rates")
+	}
+
+	config = StructuredLoggerConfig{
+		SampleRate:           NeverSample,
+		DiagnosticSampleRate: 1,
+		PerRouteSampleRate:   map[string]float64{"/health": NeverSample},
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+// Package sentry reports panics and server errors caught by the gin-logger
+// middleware to Sentry, through the ginlogger.ErrorReporter hook.
+package sentry
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+
+	ginlogger "github.com/csmart-libs/gin-logger"
+)
+
+// Reporter is a ginlogger.ErrorReporter capturing events to Sentry. Use it as
+// RecoveryLoggerConfig.Reporter and ErrorLoggerConfig.Reporter.
+type Reporter struct {
+	hub *sentry.Hub
+}
+
+var _ ginlogger.ErrorReporter = (*Reporter)(nil)
+
+// NewReporter returns a Reporter capturing events with hub, or with the
+// current hub when nil
+func NewReporter(hub *sentry.Hub) *Reporter {
+	if hub == nil {
+		hub = sentry.CurrentHub()
+	}
+	return &Reporter{hub: hub}
+}
+
+// ReportPanic captures a recovered panic
+func (r *Reporter) ReportPanic(c *gin.Context, recovered any) {
+	hub := r.requestHub(c)
+
+	// Report errors as exceptions so Sentry keeps their type
+	if err, ok := recovered.(error); ok {
+		hub.CaptureException(err)
+		return
+	}
+	hub.CaptureException(errors.New(fmt.Sprint(recovered)))
+}
+
+// ReportError captures an error of a 5xx response
+func (r *Reporter) ReportError(c *gin.Context, err error) {
+	r.requestHub(c).CaptureException(err)
+}
+
+// requestHub returns a hub whose scope carries the request as context and the
+// method, path, request_id and user_id as tags
+func (r *Reporter) requestHub(c *gin.Context) *sentry.Hub {
+	hub := r.hub.Clone()
+	scope := hub.Scope()
+
+	scope.SetRequest(c.Request)
+	scope.SetTag("method", c.Request.Method)
+	scope.SetTag("path", c.Request.URL.Path)
+	if requestID := c.GetString("request_id"); requestID != "" {
+		scope.SetTag("request_id", requestID)
+	}
+	if userID := c.GetString("user_id"); userID != "" {
+		scope.SetTag("user_id", userID)
+	}
+
+	return hub
+}
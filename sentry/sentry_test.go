@@ -0,0 +1,143 @@
+package sentry
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	ginlogger "github.com/csmart-libs/gin-logger"
+	"github.com/getsentry/sentry-go"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// recordingTransport keeps the events a client sends
+type recordingTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *recordingTransport) Flush(time.Duration) bool       { return true }
+func (t *recordingTransport) Configure(sentry.ClientOptions) {}
+
+func (t *recordingTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *recordingTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event(nil), t.events...)
+}
+
+// newTestReporter returns a Reporter sending to a recordingTransport
+func newTestReporter(t *testing.T) (*Reporter, *recordingTransport) {
+	t.Helper()
+
+	transport := &recordingTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Dsn: "https://key@sentry.example.com/1", Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewReporter(sentry.NewHub(client, sentry.NewScope())), transport
+}
+
+// serve runs req through an engine with the reporter hooked into the
+// recovery and error middleware
+func serve(reporter *Reporter, req *http.Request, handler gin.HandlerFunc) {
+	logger := ginlogger.NewWriterLogger(&discard{}, "info")
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("request_id", "req-1")
+		c.Set("user_id", "user-7")
+	})
+	r.Use(ginlogger.ErrorLoggerWithConfig(ginlogger.ErrorLoggerConfig{Logger: logger, Reporter: reporter}))
+	r.Use(ginlogger.RecoveryLoggerWithConfig(ginlogger.RecoveryLoggerConfig{Logger: logger, Reporter: reporter}))
+	r.Any("/*path", handler)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+type discard struct{}
+
+func (discard) Write(p []byte) (int, error) { return len(p), nil }
+
+func TestReportPanic(t *testing.T) {
+	reporter, transport := newTestReporter(t)
+	serve(reporter, httptest.NewRequest(http.MethodPost, "/orders", nil), func(c *gin.Context) {
+		panic("inventory out of sync")
+	})
+
+	events := transport.Events()
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want the panic only", len(events))
+	}
+	event := events[0]
+	if len(event.Exception) == 0 || event.Exception[len(event.Exception)-1].Value != "inventory out of sync" {
+		t.Errorf("exception = %+v, want the panic value", event.Exception)
+	}
+	for tag, want := range map[string]string{"method": "POST", "path": "/orders", "request_id": "req-1", "user_id": "user-7"} {
+		if event.Tags[tag] != want {
+			t.Errorf("tag %s = %q, want %q", tag, event.Tags[tag], want)
+		}
+	}
+	if event.Request == nil || event.Request.Method != http.MethodPost {
+		t.Errorf("request context = %+v, want the POST request", event.Request)
+	}
+}
+
+func TestReportError(t *testing.T) {
+	reporter, transport := newTestReporter(t)
+	errUpstream := errors.New("upstream unavailable")
+
+	serve(reporter, httptest.NewRequest(http.MethodGet, "/bad", nil), func(c *gin.Context) {
+		c.Error(errors.New("invalid input"))
+		c.Status(http.StatusBadRequest)
+	})
+	serve(reporter, httptest.NewRequest(http.MethodGet, "/fail", nil), func(c *gin.Context) {
+		c.Error(errUpstream)
+		c.Status(http.StatusBadGateway)
+	})
+	serve(reporter, httptest.NewRequest(http.MethodGet, "/silent", nil), func(c *gin.Context) {
+		c.Status(http.StatusServiceUnavailable)
+	})
+
+	events := transport.Events()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want one per 5xx response", len(events))
+	}
+	want := []string{"upstream unavailable", "503 Service Unavailable"}
+	for i, event := range events {
+		exception := event.Exception[len(event.Exception)-1]
+		if exception.Value != want[i] {
+			t.Errorf("event %d exception = %q, want %q", i, exception.Value, want[i])
+		}
+	}
+	if events[1].Tags["path"] != "/silent" {
+		t.Errorf("path tag = %q, want /silent", events[1].Tags["path"])
+	}
+}
+
+func TestRequestHubIsolated(t *testing.T) {
+	reporter, transport := newTestReporter(t)
+	serve(reporter, httptest.NewRequest(http.MethodGet, "/fail", nil), func(c *gin.Context) {
+		c.Status(http.StatusInternalServerError)
+	})
+
+	reporter.hub.CaptureMessage("outside a request")
+	events := transport.Events()
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	if _, ok := events[1].Tags["path"]; ok {
+		t.Errorf("request tags leaked into the shared hub: %v", events[1].Tags)
+	}
+}
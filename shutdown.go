@@ -0,0 +1,88 @@
+package ginlogger
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+var shuttingDown atomic.Bool
+
+// backgroundWorkers holds the Close functions of goroutines started by
+// middleware (summaries, throttles) and by asynchronous outputs (buffered,
+// HTTP, GELF), stopped by Shutdown
+var backgroundWorkers struct {
+	mu      sync.Mutex
+	closes  []func()
+	outputs []func()
+}
+
+// registerWorker registers stop to be called by Shutdown
+func registerWorker(stop func()) {
+	backgroundWorkers.mu.Lock()
+	defer backgroundWorkers.mu.Unlock()
+
+	backgroundWorkers.closes = append(backgroundWorkers.closes, stop)
+}
+
+// registerOutput registers stop to be called by Shutdown after the workers,
+// so that their final entries still reach the output
+func registerOutput(stop func()) {
+	backgroundWorkers.mu.Lock()
+	defer backgroundWorkers.mu.Unlock()
+
+	backgroundWorkers.outputs = append(backgroundWorkers.outputs, stop)
+}
+
+// Shutdown stops the background goroutines started by middleware, logging
+// their final entries (e.g. the last "Request summary"), then delivers and
+// closes the asynchronous outputs and finally flushes all loggers. Call it
+// after http.Server.Shutdown has returned; middleware keeps handling requests
+// afterwards but no longer emits periodic entries.
+func Shutdown() error {
+	backgroundWorkers.mu.Lock()
+	closes := backgroundWorkers.closes
+	outputs := backgroundWorkers.outputs
+	backgroundWorkers.closes = nil
+	backgroundWorkers.outputs = nil
+	backgroundWorkers.mu.Unlock()
+
+	for _, stop := range closes {
+		stop()
+	}
+	for _, stop := range outputs {
+		stop()
+	}
+	return Flush()
+}
+
+// MarkShuttingDown flags that graceful shutdown has started, e.g. right before
+// calling http.Server.Shutdown. StructuredLogger then emits
+// during_shutdown: true for requests completing afterwards, which explains
+// elevated latency or errors during deploys.
+func MarkShuttingDown() {
+	shuttingDown.Store(true)
+}
+
+// ResetShuttingDown clears the flag set by MarkShuttingDown, e.g. when a
+// shutdown was aborted
+func ResetShuttingDown() {
+	shuttingDown.Store(false)
+}
+
+// cancellationReason classifies why ctx was cancelled: deadline_exceeded,
+// server_shutdown (cancelled after MarkShuttingDown) or client_cancelled.
+// It returns "" for a live context.
+func cancellationReason(ctx context.Context) string {
+	switch err := ctx.Err(); {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case shuttingDown.Load():
+		return "server_shutdown"
+	default:
+		return "client_cancelled"
+	}
+}
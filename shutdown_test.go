@@ -0,0 +1,79 @@
+package ginlogger
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMarkShuttingDown(t *testing.T) {
+	t.Cleanup(ResetShuttingDown)
+
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+	MarkShuttingDown()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+	ResetShuttingDown()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, middleware)
+
+	for i, want := range []bool{false, true, false} {
+		entry := buf.entries(t)[i]
+		if _, flagged := entry["during_shutdown"]; flagged != want {
+			t.Errorf("request %d: during_shutdown logged = %v, want %v", i, flagged, want)
+		}
+	}
+}
+
+func TestCancellationReason(t *testing.T) {
+	t.Cleanup(ResetShuttingDown)
+
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if reason := cancellationReason(context.Background()); reason != "" {
+		t.Errorf("live context reason = %q, want none", reason)
+	}
+	if reason := cancellationReason(expired); reason != "deadline_exceeded" {
+		t.Errorf("expired context reason = %q, want deadline_exceeded", reason)
+	}
+	if reason := cancellationReason(cancelled); reason != "client_cancelled" {
+		t.Errorf("cancelled context reason = %q, want client_cancelled", reason)
+	}
+
+	MarkShuttingDown()
+	if reason := cancellationReason(cancelled); reason != "server_shutdown" {
+		t.Errorf("cancelled context reason during shutdown = %q, want server_shutdown", reason)
+	}
+	if reason := cancellationReason(expired); reason != "deadline_exceeded" {
+		t.Errorf("expired context reason during shutdown = %q, want deadline_exceeded", reason)
+	}
+}
+
+func TestLogCancellationReason(t *testing.T) {
+	logger, buf := newTestLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	serve(req, "/", func(c *gin.Context) {
+		// The client disconnects while the handler runs
+		cancel()
+		c.Status(http.StatusOK)
+	}, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	entries := buf.entries(t)
+	if entries[0]["cancellation_reason"] != "client_cancelled" {
+		t.Errorf("cancellation_reason = %v, want client_cancelled", entries[0]["cancellation_reason"])
+	}
+	if _, ok := entries[1]["cancellation_reason"]; ok {
+		t.Errorf("cancellation_reason logged for a completed request: %v", entries[1])
+	}
+}
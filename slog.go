@@ -0,0 +1,92 @@
+package ginlogger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogLogger adapts a log/slog handler to the Logger interface so the
+// middleware can be used in slog-first codebases
+type SlogLogger struct {
+	logger *slog.Logger
+}
+
+// NewSlogLogger returns a Logger that emits entries to the given slog.Handler
+func NewSlogLogger(handler slog.Handler) *SlogLogger {
+	return &SlogLogger{logger: slog.New(handler)}
+}
+
+// StructuredLoggerSlog returns a StructuredLogger middleware that emits to the
+// given slog.Handler instead of the go-logger/zap Logger. The config Logger is ignored.
+func StructuredLoggerSlog(handler slog.Handler, config StructuredLoggerConfig) gin.HandlerFunc {
+	config.Logger = NewSlogLogger(handler)
+	return StructuredLogger(config)
+}
+
+func (l *SlogLogger) Debug(msg string, fields ...zap.Field) {
+	l.log(slog.LevelDebug, msg, fields)
+}
+
+func (l *SlogLogger) Info(msg string, fields ...zap.Field) {
+	l.log(slog.LevelInfo, msg, fields)
+}
+
+func (l *SlogLogger) Warn(msg string, fields ...zap.Field) {
+	l.log(slog.LevelWarn, msg, fields)
+}
+
+func (l *SlogLogger) Error(msg string, fields ...zap.Field) {
+	l.log(slog.LevelError, msg, fields)
+}
+
+func (l *SlogLogger) Fatal(msg string, fields ...zap.Field) {
+	l.log(slog.LevelError, msg, fields)
+	os.Exit(1)
+}
+
+func (l *SlogLogger) Panic(msg string, fields ...zap.Field) {
+	l.log(slog.LevelError, msg, fields)
+	panic(msg)
+}
+
+func (l *SlogLogger) With(fields ...zap.Field) Logger {
+	return &SlogLogger{logger: l.logger.With(attrsToArgs(fieldsToAttrs(fields))...)}
+}
+
+func (l *SlogLogger) Sync() error {
+	return nil
+}
+
+func (l *SlogLogger) log(level slog.Level, msg string, fields []zap.Field) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	l.logger.LogAttrs(ctx, level, msg, fieldsToAttrs(fields)...)
+}
+
+// fieldsToAttrs converts zap fields to slog attributes, preserving their order
+func fieldsToAttrs(fields []zap.Field) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, field := range fields {
+		encoder := zapcore.NewMapObjectEncoder()
+		field.AddTo(encoder)
+		for key, value := range encoder.Fields {
+			attrs = append(attrs, slog.Any(key, value))
+		}
+	}
+	return attrs
+}
+
+func attrsToArgs(attrs []slog.Attr) []any {
+	args := make([]any, len(attrs))
+	for i, attr := range attrs {
+		args[i] = attr
+	}
+	return args
+}
@@ -0,0 +1,44 @@
+package ginlogger
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestStructuredLoggerSlog(t *testing.T) {
+	buf := &syncBuffer{}
+	handler := slog.NewJSONHandler(buf, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+	serve(req, "/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) }, StructuredLoggerSlog(handler, StructuredLoggerConfig{}))
+
+	entries := buf.entries(t)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v, want one", entries)
+	}
+	entry := entries[0]
+	if entry["msg"] != "Client error" || entry["level"] != "WARN" || entry["status"] != float64(404) || entry["path"] != "/missing" {
+		t.Fatalf("entry = %v, want a WARN client error for /missing", entry)
+	}
+}
+
+func TestSlogLogger(t *testing.T) {
+	buf := &syncBuffer{}
+	logger := NewSlogLogger(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	logger.Debug("filtered")
+	logger.With(zap.String("service", "api")).Info("Started", zap.Int("port", 8080), zap.Bool("tls", true))
+
+	entries := buf.entries(t)
+	if len(entries) != 1 {
+		t.Fatalf("entries = %v, want only the info entry", entries)
+	}
+	if entries[0]["service"] != "api" || entries[0]["port"] != float64(8080) || entries[0]["tls"] != true {
+		t.Fatalf("entry = %v, want the converted fields", entries[0])
+	}
+}
@@ -0,0 +1,331 @@
+package ginlogger
+
+import (
+	"bytes"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// snapshotFields resolves fields that are encoded lazily (object and array
+// marshalers, stringers, errors, byte slices) into fields holding copies of
+// their current values, so that an entry written by another goroutine does
+// not read request state its caller may reuse, such as the route params of a
+// pooled gin.Context. The snapshot encodes exactly like the original fields.
+// Skipped fields (e.g. the request context) are kept as they are, as are the
+// values of reflected fields.
+func snapshotFields(fields []zap.Field) []zap.Field {
+	snapshot := &objectSnapshot{fields: make([]zap.Field, 0, len(fields))}
+	for _, field := range fields {
+		if field.Type == zapcore.SkipType {
+			snapshot.fields = append(snapshot.fields, field)
+			continue
+		}
+		field.AddTo(snapshot)
+	}
+	return snapshot.fields
+}
+
+// objectSnapshot records what an object adds to its encoder as fields and
+// replays them when marshaled
+type objectSnapshot struct {
+	fields []zap.Field
+}
+
+func (o *objectSnapshot) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, field := range o.fields {
+		field.AddTo(enc)
+	}
+	return nil
+}
+
+func (o *objectSnapshot) add(field zap.Field) {
+	o.fields = append(o.fields, field)
+}
+
+func (o *objectSnapshot) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	array := &arraySnapshot{}
+	err := marshaler.MarshalLogArray(array)
+	o.add(zap.Array(key, array))
+	return err
+}
+
+func (o *objectSnapshot) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	object := &objectSnapshot{}
+	err := marshaler.MarshalLogObject(object)
+	o.add(zap.Object(key, object))
+	return err
+}
+
+func (o *objectSnapshot) AddBinary(key string, value []byte) {
+	o.add(zap.Binary(key, bytes.Clone(value)))
+}
+
+func (o *objectSnapshot) AddByteString(key string, value []byte) {
+	o.add(zap.ByteString(key, bytes.Clone(value)))
+}
+
+func (o *objectSnapshot) AddBool(key string, value bool) {
+	o.add(zap.Bool(key, value))
+}
+
+func (o *objectSnapshot) AddComplex128(key string, value complex128) {
+	o.add(zap.Complex128(key, value))
+}
+
+func (o *objectSnapshot) AddComplex64(key string, value complex64) {
+	o.add(zap.Complex64(key, value))
+}
+
+func (o *objectSnapshot) AddDuration(key string, value time.Duration) {
+	o.add(zap.Duration(key, value))
+}
+
+func (o *objectSnapshot) AddFloat64(key string, value float64) {
+	o.add(zap.Float64(key, value))
+}
+
+func (o *objectSnapshot) AddFloat32(key string, value float32) {
+	o.add(zap.Float32(key, value))
+}
+
+func (o *objectSnapshot) AddInt(key string, value int) {
+	o.add(zap.Int(key, value))
+}
+
+func (o *objectSnapshot) AddInt64(key string, value int64) {
+	o.add(zap.Int64(key, value))
+}
+
+func (o *objectSnapshot) AddInt32(key string, value int32) {
+	o.add(zap.Int32(key, value))
+}
+
+func (o *objectSnapshot) AddInt16(key string, value int16) {
+	o.add(zap.Int16(key, value))
+}
+
+func (o *objectSnapshot) AddInt8(key string, value int8) {
+	o.add(zap.Int8(key, value))
+}
+
+func (o *objectSnapshot) AddString(key, value string) {
+	o.add(zap.String(key, value))
+}
+
+func (o *objectSnapshot) AddTime(key string, value time.Time) {
+	o.add(zap.Time(key, value))
+}
+
+func (o *objectSnapshot) AddUint(key string, value uint) {
+	o.add(zap.Uint(key, value))
+}
+
+func (o *objectSnapshot) AddUint64(key string, value uint64) {
+	o.add(zap.Uint64(key, value))
+}
+
+func (o *objectSnapshot) AddUint32(key string, value uint32) {
+	o.add(zap.Uint32(key, value))
+}
+
+func (o *objectSnapshot) AddUint16(key string, value uint16) {
+	o.add(zap.Uint16(key, value))
+}
+
+func (o *objectSnapshot) AddUint8(key string, value uint8) {
+	o.add(zap.Uint8(key, value))
+}
+
+func (o *objectSnapshot) AddUintptr(key string, value uintptr) {
+	o.add(zap.Uintptr(key, value))
+}
+
+func (o *objectSnapshot) AddReflected(key string, value any) error {
+	o.add(zap.Reflect(key, value))
+	return nil
+}
+
+func (o *objectSnapshot) OpenNamespace(key string) {
+	o.add(zap.Namespace(key))
+}
+
+// arraySnapshot records what an array appends to its encoder and replays it
+// when marshaled
+type arraySnapshot struct {
+	elements []func(zapcore.ArrayEncoder) error
+}
+
+func (a *arraySnapshot) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	for _, element := range a.elements {
+		if err := element(enc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *arraySnapshot) add(element func(zapcore.ArrayEncoder) error) {
+	a.elements = append(a.elements, element)
+}
+
+func (a *arraySnapshot) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	array := &arraySnapshot{}
+	err := marshaler.MarshalLogArray(array)
+	a.add(func(enc zapcore.ArrayEncoder) error { return enc.AppendArray(array) })
+	return err
+}
+
+func (a *arraySnapshot) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	object := &objectSnapshot{}
+	err := marshaler.MarshalLogObject(object)
+	a.add(func(enc zapcore.ArrayEncoder) error { return enc.AppendObject(object) })
+	return err
+}
+
+func (a *arraySnapshot) AppendReflected(value any) error {
+	a.add(func(enc zapcore.ArrayEncoder) error { return enc.AppendReflected(value) })
+	return nil
+}
+
+func (a *arraySnapshot) AppendByteString(value []byte) {
+	value = bytes.Clone(value)
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendByteString(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendBool(value bool) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendBool(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendComplex128(value complex128) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendComplex128(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendComplex64(value complex64) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendComplex64(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendDuration(value time.Duration) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendDuration(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendFloat64(value float64) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendFloat64(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendFloat32(value float32) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendFloat32(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendInt(value int) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendInt64(value int64) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt64(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendInt32(value int32) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt32(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendInt16(value int16) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt16(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendInt8(value int8) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendInt8(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendString(value string) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendString(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendTime(value time.Time) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendTime(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendUint(value uint) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendUint(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendUint64(value uint64) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendUint64(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendUint32(value uint32) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendUint32(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendUint16(value uint16) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendUint16(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendUint8(value uint8) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendUint8(value)
+		return nil
+	})
+}
+
+func (a *arraySnapshot) AppendUintptr(value uintptr) {
+	a.add(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendUintptr(value)
+		return nil
+	})
+}
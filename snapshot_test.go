@@ -0,0 +1,67 @@
+package ginlogger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// mutableObject logs its current state lazily, like request state held by a
+// pooled gin.Context
+type mutableObject struct {
+	name  string
+	items []string
+}
+
+func (o *mutableObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("name", o.name)
+	enc.AddDuration("latency", time.Second)
+	return enc.AddArray("items", zapcore.ArrayMarshalerFunc(func(arr zapcore.ArrayEncoder) error {
+		for _, item := range o.items {
+			arr.AppendString(item)
+		}
+		return arr.AppendObject(zapcore.ObjectMarshalerFunc(func(enc zapcore.ObjectEncoder) error {
+			enc.AddInt("count", len(o.items))
+			return nil
+		}))
+	}))
+}
+
+// encodeFields returns the JSON encoding of an entry with fields
+func encodeFields(t *testing.T, fields []zap.Field) string {
+	t.Helper()
+
+	encoder := zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	buf, err := encoder.EncodeEntry(zapcore.Entry{Message: "entry"}, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestSnapshotFields(t *testing.T) {
+	object := &mutableObject{name: "before", items: []string{"a", "b"}}
+	raw := []byte("raw")
+	fields := []zap.Field{
+		zap.Object("object", object),
+		zap.Inline(object),
+		zap.Strings("list", object.items),
+		zap.ByteString("bytes", raw),
+		zap.Error(errors.New("failed")),
+		zap.Stringer("stringer", time.Second),
+		zap.Int("status", 200),
+	}
+	want := encodeFields(t, fields)
+
+	snapshot := snapshotFields(fields)
+	object.name = "after"
+	object.items[0] = "changed"
+	raw[0] = 'X'
+
+	if got := encodeFields(t, snapshot); got != want {
+		t.Fatalf("snapshot encodes as %s, want %s", got, want)
+	}
+}
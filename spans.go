@@ -0,0 +1,102 @@
+package ginlogger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// spanRecord is a single downstream call reported by a handler
+type spanRecord struct {
+	name     string
+	duration time.Duration
+}
+
+// spanRecords collects the spans reported for a request
+type spanRecords struct {
+	mu    sync.Mutex
+	spans []spanRecord
+}
+
+// RecordSpan reports that the handler spent duration in the named downstream
+// call (e.g. "db.query"). StructuredLogger emits all spans as a spans array and
+// logs a separate slow dependency warning for spans exceeding their
+// SlowSpanThresholds entry.
+func RecordSpan(c *gin.Context, name string, duration time.Duration) {
+	value, _ := c.Get(spansKey)
+	records, ok := value.(*spanRecords)
+	if !ok {
+		records = &spanRecords{}
+		c.Set(spansKey, records)
+	}
+
+	records.mu.Lock()
+	defer records.mu.Unlock()
+	records.spans = append(records.spans, spanRecord{name: name, duration: duration})
+}
+
+// recordedSpans returns the spans reported via RecordSpan
+func recordedSpans(c *gin.Context) *spanRecords {
+	value, _ := c.Get(spansKey)
+	records, _ := value.(*spanRecords)
+	return records
+}
+
+// slowSpans returns the spans exceeding their threshold
+func (r *spanRecords) slowSpans(thresholds map[string]time.Duration) []spanRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var slow []spanRecord
+	for _, span := range r.spans {
+		if threshold, ok := thresholds[span.name]; ok && span.duration > threshold {
+			slow = append(slow, span)
+		}
+	}
+	return slow
+}
+
+func (r *spanRecords) MarshalLogArray(encoder zapcore.ArrayEncoder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, span := range r.spans {
+		if err := encoder.AppendObject(span); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s spanRecord) MarshalLogObject(encoder zapcore.ObjectEncoder) error {
+	encoder.AddString("name", s.name)
+	encoder.AddDuration("duration", s.duration)
+	return nil
+}
+
+// logSlowSpans logs a warning for each recorded span exceeding its threshold
+func logSlowSpans(c *gin.Context, logger Logger, thresholds map[string]time.Duration) {
+	records := recordedSpans(c)
+	if records == nil || len(thresholds) == 0 {
+		return
+	}
+
+	for _, span := range records.slowSpans(thresholds) {
+		fields := []zap.Field{
+			zap.String("slow_dependency", span.name),
+			zap.Duration("duration", span.duration),
+			zap.Duration("threshold", thresholds[span.name]),
+			zap.String("method", c.Request.Method),
+			zap.String("route", c.FullPath()),
+		}
+
+		if requestID := c.GetString("request_id"); requestID != "" {
+			fields = append(fields, zap.String("request_id", requestID))
+		}
+
+		logger.Warn("Slow dependency", fields...)
+	}
+}
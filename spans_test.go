@@ -0,0 +1,62 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRecordSpan(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:             logger,
+		SlowSpanThresholds: map[string]time.Duration{"db.query": 100 * time.Millisecond, "cache.get": time.Second},
+	})
+	handler := func(c *gin.Context) {
+		RecordSpan(c, "db.query", 250*time.Millisecond)
+		RecordSpan(c, "cache.get", 5*time.Millisecond)
+		RecordSpan(c, "queue.publish", 2*time.Second)
+		c.Status(http.StatusOK)
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/orders", nil), "/orders", handler, middleware)
+
+	entries := buf.entries(t)
+	slow := findEntry(t, entries, "Slow dependency")
+	if slow["slow_dependency"] != "db.query" || slow["duration"] != 0.25 || slow["threshold"] != 0.1 || slow["route"] != "/orders" {
+		t.Errorf("warning = %v, want db.query exceeding its threshold", slow)
+	}
+	if len(entries) != 2 {
+		t.Errorf("got %d entries, want one warning for the span with an exceeded threshold", len(entries))
+	}
+
+	spans, _ := findEntry(t, entries, "Request completed")["spans"].([]any)
+	if len(spans) != 3 {
+		t.Fatalf("spans = %v, want all three recorded spans", spans)
+	}
+	if first, _ := spans[0].(map[string]any); first["name"] != "db.query" || first["duration"] != 0.25 {
+		t.Errorf("spans[0] = %v, want db.query with its duration", spans[0])
+	}
+}
+
+func TestSlowSpanNotSampledOut(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:             logger,
+		SampleRate:         NeverSample,
+		SlowSpanThresholds: map[string]time.Duration{"db.query": time.Millisecond},
+	})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) {
+		RecordSpan(c, "db.query", time.Second)
+		c.Status(http.StatusOK)
+	}, middleware)
+
+	entries := buf.entries(t)
+	if len(entries) != 1 || entries[0]["msg"] != "Slow dependency" {
+		t.Fatalf("entries = %v, want only the slow dependency warning", entries)
+	}
+}
@@ -0,0 +1,64 @@
+package ginlogger
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// processStart is captured when the package is initialized and used as the
+// server start time for server_uptime
+var processStart = time.Now()
+
+// RequestCounts holds per-status-class request counters
+type RequestCounts struct {
+	Total     uint64 `json:"total"`
+	Status2xx uint64 `json:"2xx"`
+	Status3xx uint64 `json:"3xx"`
+	Status4xx uint64 `json:"4xx"`
+	Status5xx uint64 `json:"5xx"`
+}
+
+// requestCounters are updated atomically by StructuredLogger
+var requestCounters struct {
+	total     atomic.Uint64
+	status2xx atomic.Uint64
+	status3xx atomic.Uint64
+	status4xx atomic.Uint64
+	status5xx atomic.Uint64
+}
+
+// RequestStats returns the request counts recorded by StructuredLogger since
+// start (or the last ResetStats), e.g. for a lightweight /stats handler
+func RequestStats() RequestCounts {
+	return RequestCounts{
+		Total:     requestCounters.total.Load(),
+		Status2xx: requestCounters.status2xx.Load(),
+		Status3xx: requestCounters.status3xx.Load(),
+		Status4xx: requestCounters.status4xx.Load(),
+		Status5xx: requestCounters.status5xx.Load(),
+	}
+}
+
+// ResetStats resets all request counters to zero
+func ResetStats() {
+	requestCounters.total.Store(0)
+	requestCounters.status2xx.Store(0)
+	requestCounters.status3xx.Store(0)
+	requestCounters.status4xx.Store(0)
+	requestCounters.status5xx.Store(0)
+}
+
+func recordRequestStatus(status int) {
+	requestCounters.total.Add(1)
+
+	switch {
+	case status >= 500:
+		requestCounters.status5xx.Add(1)
+	case status >= 400:
+		requestCounters.status4xx.Add(1)
+	case status >= 300:
+		requestCounters.status3xx.Add(1)
+	case status >= 200:
+		requestCounters.status2xx.Add(1)
+	}
+}
@@ -0,0 +1,57 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequestStatsConcurrent(t *testing.T) {
+	ResetStats()
+	t.Cleanup(ResetStats)
+
+	logger, _ := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+	r.GET("/status/:code", func(c *gin.Context) {
+		code, _ := strconv.Atoi(c.Param("code"))
+		c.Status(code)
+	})
+
+	statuses := []int{200, 204, 301, 404, 429, 500, 503}
+	const perStatus = 50
+
+	var wg sync.WaitGroup
+	for _, status := range statuses {
+		for range perStatus {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/status/"+strconv.Itoa(status), nil))
+			}()
+		}
+		// Reading while requests complete must be race free
+		RequestStats()
+	}
+	wg.Wait()
+
+	want := RequestCounts{
+		Total:     uint64(len(statuses) * perStatus),
+		Status2xx: 2 * perStatus,
+		Status3xx: perStatus,
+		Status4xx: 2 * perStatus,
+		Status5xx: 2 * perStatus,
+	}
+	if got := RequestStats(); got != want {
+		t.Fatalf("RequestStats() = %+v, want %+v", got, want)
+	}
+
+	ResetStats()
+	if got := RequestStats(); got != (RequestCounts{}) {
+		t.Fatalf("RequestStats() after ResetStats = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,267 @@
+package ginlogger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+func TestDisableDefaultFields(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{
+		Logger:               logger,
+		DisableDefaultFields: true,
+		LogClientIP:          true,
+		CustomFields: func(c *gin.Context) []zap.Field {
+			return []zap.Field{zap.String("tenant", "acme")}
+		},
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/users?page=2", nil)
+	serve(req, "/users", func(c *gin.Context) {
+		c.Set("request_id", "req-1")
+		c.Status(http.StatusOK)
+	}, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	for _, key := range []string{"method", "path", "status", "latency", "body_size", "query", "request_id"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("default field %s logged: %v", key, entry)
+		}
+	}
+	if entry["tenant"] != "acme" || entry["ip"] == nil {
+		t.Fatalf("entry = %v, want the custom and enabled fields", entry)
+	}
+}
+
+func TestLogRequestStart(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestStart: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set("X-Request-ID", "req-1")
+	serve(req, "/orders", func(c *gin.Context) {
+		// The start entry is written before the handler runs
+		if n := len(buf.entries(t)); n != 1 {
+			t.Errorf("%d entries before the handler, want the start entry", n)
+		}
+		c.Status(http.StatusOK)
+	}, RequestIDMiddleware(), middleware)
+
+	entries := buf.entries(t)
+	if len(entries) != 2 || entries[0]["msg"] != "Request started" || entries[1]["msg"] != "Request completed" {
+		t.Fatalf("entries = %v, want a start and a completion entry", entries)
+	}
+	if entries[0]["method"] != "GET" || entries[0]["path"] != "/orders" || entries[0]["request_id"] != "req-1" {
+		t.Fatalf("start entry = %v, want method, path and request_id", entries[0])
+	}
+}
+
+func TestLogRequestLine(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRequestLine: true})
+
+	serve(httptest.NewRequest(http.MethodPost, "/search?q=gin&page=2", nil), "/search", ok, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Request completed")
+	if entry["request_line"] != "POST /search?q=gin&page=2 HTTP/1.1" {
+		t.Fatalf("request_line = %v, want the reconstructed request line", entry["request_line"])
+	}
+}
+
+func TestRateLimitedRequests(t *testing.T) {
+	logger, buf := newTestLogger()
+	rateLimitLogger, rateLimitBuf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, RateLimitLogger: rateLimitLogger, RateLimitLevel: LevelInfo}))
+	r.GET("/limited", func(c *gin.Context) { c.Status(http.StatusTooManyRequests) })
+	r.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/limited", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	limited := findEntry(t, rateLimitBuf.entries(t), "Rate limited")
+	if limited["rate_limited"] != true || limited["level"] != "info" || limited["status"] != float64(429) {
+		t.Fatalf("rate limited entry = %v, want rate_limited at info", limited)
+	}
+
+	entries := buf.entries(t)
+	if len(entries) != 1 || entries[0]["msg"] != "Client error" || entries[0]["rate_limited"] != nil {
+		t.Fatalf("main entries = %v, want only the unmarked 404", entries)
+	}
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, RateLimitStatus: http.StatusServiceUnavailable})
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) { c.Status(http.StatusServiceUnavailable) }, middleware)
+
+	entry := findEntry(t, buf.entries(t), "Rate limited")
+	if entry["rate_limited"] != true || entry["level"] != "error" {
+		t.Fatalf("entry = %v, want the 503 marked as rate limited at its status level", entry)
+	}
+}
+
+func TestLogClientCert(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, LogClientCert: true}))
+	r.GET("/", ok)
+
+	cert := &x509.Certificate{
+		Subject:      pkix.Name{CommonName: "billing-service", Organization: []string{"Acme"}},
+		SerialNumber: big.NewInt(4242),
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Without a client certificate no fields are added
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	entries := buf.entries(t)
+	if entries[0]["client_cert_subject"] != "CN=billing-service,O=Acme" || entries[0]["client_cert_serial"] != "4242" {
+		t.Fatalf("entry = %v, want the certificate subject and serial", entries[0])
+	}
+	if _, ok := entries[1]["client_cert_subject"]; ok {
+		t.Fatalf("entry = %v, want no certificate fields", entries[1])
+	}
+}
+
+func TestLogStatusText(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, LogStatusText: true}))
+	r.GET("/missing", func(c *gin.Context) { c.Status(http.StatusNotFound) })
+	r.GET("/custom", func(c *gin.Context) { c.Status(299) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/custom", nil))
+
+	entries := buf.entries(t)
+	if entries[0]["status_text"] != "Not Found" {
+		t.Fatalf("status_text = %v, want Not Found", entries[0]["status_text"])
+	}
+	if _, ok := entries[1]["status_text"]; ok {
+		t.Fatalf("entry = %v, want no status_text for a non-standard code", entries[1])
+	}
+}
+
+func TestLogRouteMatched(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, LogRouteMatched: true}))
+	r.GET("/users/:id", ok)
+	r.NoRoute(func(c *gin.Context) { c.Status(http.StatusNotFound) })
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	entries := buf.entries(t)
+	if entries[0]["route_matched"] != true || entries[1]["route_matched"] != false {
+		t.Fatalf("entries = %v, want the NoRoute fallback marked unmatched", entries)
+	}
+}
+
+func TestLogTTFB(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, LogTTFB: true})
+	streaming := func(c *gin.Context) {
+		c.String(http.StatusOK, "first chunk")
+		c.Writer.Flush()
+		time.Sleep(50 * time.Millisecond)
+		c.Writer.WriteString("last chunk")
+	}
+
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", streaming, middleware)
+
+	entry := buf.entries(t)[0]
+	ttfb, ok := entry["ttfb"].(float64)
+	if !ok {
+		t.Fatalf("ttfb missing: %v", entry)
+	}
+	if latency := entry["latency"].(float64); latency-ttfb < 0.04 {
+		t.Errorf("ttfb = %vs, latency = %vs, want the first byte before the 50ms pause", ttfb, latency)
+	}
+}
+
+func TestLogUptime(t *testing.T) {
+	logger, buf := newTestLogger()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger, LogUptime: true}))
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	entries := buf.entries(t)
+	uptime, ok := entries[0]["server_uptime"].(float64)
+	if !ok || uptime <= 0 || uptime > time.Since(processStart).Seconds() {
+		t.Errorf("server_uptime = %v, want the time since the process started", entries[0]["server_uptime"])
+	}
+	if _, ok := entries[1]["server_uptime"]; ok {
+		t.Error("server_uptime logged without LogUptime")
+	}
+}
+
+func TestSkipStatuses(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, SkipStatuses: []int{http.StatusNotModified}})
+
+	for _, status := range []int{http.StatusOK, http.StatusNotModified, http.StatusNotFound} {
+		serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", func(c *gin.Context) { c.Status(status) }, middleware)
+	}
+
+	entries := buf.entries(t)
+	if len(entries) != 2 || entries[0]["status"] != float64(200) || entries[1]["status"] != float64(404) {
+		t.Errorf("entries = %v, want the 200 and 404 responses only", entries)
+	}
+}
+
+func TestRequestTooLarge(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, MaxAllowedSize: 1024})
+	limiter := func(c *gin.Context) {
+		if c.Request.ContentLength > 1024 {
+			c.AbortWithStatus(http.StatusRequestEntityTooLarge)
+		}
+	}
+
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("x", 4096))), "/", ok, middleware, limiter)
+	serve(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("small")), "/", ok, middleware, limiter)
+
+	entries := buf.entries(t)
+	if entries[0]["msg"] != "Request too large" || entries[0]["size_limit_exceeded"] != true ||
+		entries[0]["declared_size"] != float64(4096) || entries[0]["max_allowed_size"] != float64(1024) {
+		t.Errorf("entry = %v, want the 413 marked with its sizes", entries[0])
+	}
+	if _, ok := entries[1]["size_limit_exceeded"]; ok {
+		t.Errorf("size_limit_exceeded logged for an accepted request: %v", entries[1])
+	}
+}
+
+func TestLogLatencyNumeric(t *testing.T) {
+	logger, buf := newTestLogger()
+	slow := func(c *gin.Context) {
+		time.Sleep(2 * time.Millisecond)
+		c.Status(http.StatusOK)
+	}
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", slow, StructuredLogger(StructuredLoggerConfig{Logger: logger, LogLatencyNs: true, LogLatencyMs: true}))
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger}))
+
+	entries := buf.entries(t)
+	ns, nsOK := entries[0]["latency_ns"].(float64)
+	ms, msOK := entries[0]["latency_ms"].(float64)
+	if !nsOK || !msOK || ns != float64(int64(ns)) || ms < 2 || ns/float64(time.Millisecond) != ms {
+		t.Errorf("latency_ns = %v, latency_ms = %v, want matching numeric latencies of at least 2ms", entries[0]["latency_ns"], entries[0]["latency_ms"])
+	}
+	if _, ok := entries[1]["latency_ns"]; ok {
+		t.Errorf("latency_ns logged without LogLatencyNs: %v", entries[1])
+	}
+}
@@ -0,0 +1,135 @@
+package ginlogger
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// summaryReservoirSize bounds the latencies kept per route and interval for
+// percentile estimation
+const summaryReservoirSize = 1024
+
+// routeSummary aggregates the requests of one route during an interval
+type routeSummary struct {
+	count        int
+	clientErrors int
+	serverErrors int
+	latencies    []time.Duration
+}
+
+// summaryAggregator collects per-route request statistics and periodically
+// logs them as "Request summary" entries
+type summaryAggregator struct {
+	mu       sync.Mutex
+	routes   map[string]*routeSummary
+	logger   Logger
+	interval time.Duration
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// newSummaryAggregator starts logging summaries to logger every interval
+// until Close is called
+func newSummaryAggregator(logger Logger, interval time.Duration) *summaryAggregator {
+	a := &summaryAggregator{
+		routes:   make(map[string]*routeSummary),
+		logger:   logger,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go a.run()
+	return a
+}
+
+func (a *summaryAggregator) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.flush()
+		case <-a.stop:
+			a.flush()
+			return
+		}
+	}
+}
+
+// Close stops the periodic summaries and logs a final one for the current
+// interval
+func (a *summaryAggregator) Close() {
+	a.once.Do(func() {
+		close(a.stop)
+	})
+	<-a.done
+}
+
+// record adds a completed request to the current interval
+func (a *summaryAggregator) record(route string, status int, latency time.Duration) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	summary, ok := a.routes[route]
+	if !ok {
+		summary = &routeSummary{}
+		a.routes[route] = summary
+	}
+
+	summary.count++
+	switch {
+	case status >= 500:
+		summary.serverErrors++
+	case status >= 400:
+		summary.clientErrors++
+	}
+
+	// Reservoir sampling keeps memory bounded at any request rate
+	if len(summary.latencies) < summaryReservoirSize {
+		summary.latencies = append(summary.latencies, latency)
+	} else if i := rand.IntN(summary.count); i < summaryReservoirSize {
+		summary.latencies[i] = latency
+	}
+}
+
+// flush logs one summary per route and starts a new interval
+func (a *summaryAggregator) flush() {
+	a.mu.Lock()
+	routes := a.routes
+	a.routes = make(map[string]*routeSummary, len(routes))
+	a.mu.Unlock()
+
+	for route, summary := range routes {
+		slices.Sort(summary.latencies)
+
+		a.logger.Info("Request summary",
+			zap.String("route", route),
+			zap.Int("count", summary.count),
+			zap.Int("client_error_count", summary.clientErrors),
+			zap.Int("server_error_count", summary.serverErrors),
+			zap.Float64("error_rate", float64(summary.serverErrors)/float64(summary.count)),
+			zap.Duration("latency_p50", percentile(summary.latencies, 0.50)),
+			zap.Duration("latency_p90", percentile(summary.latencies, 0.90)),
+			zap.Duration("latency_p99", percentile(summary.latencies, 0.99)),
+			zap.Duration("interval", a.interval),
+		)
+	}
+}
+
+// percentile returns the p-th percentile (0.0-1.0) of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	return sorted[int(p*float64(len(sorted)-1))]
+}
@@ -0,0 +1,98 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSummaryOnly(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{
+		Logger:           logger,
+		SummaryOnly:      true,
+		SummaryInterval:  50 * time.Millisecond,
+		SummaryLogErrors: true,
+	}))
+	r.GET("/items/:id", func(c *gin.Context) {
+		if c.Param("id") == "missing" {
+			c.Status(http.StatusNotFound)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	for range 10 {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/1", nil))
+	}
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/items/missing", nil))
+
+	deadline := time.Now().Add(2 * time.Second)
+	var summary map[string]any
+	for summary == nil && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		for _, entry := range buf.entries(t) {
+			if entry["msg"] == "Request summary" {
+				summary = entry
+			}
+		}
+	}
+
+	if summary == nil {
+		t.Fatal("no periodic summary logged")
+	}
+	if summary["route"] != "/items/:id" || summary["count"] != float64(11) || summary["client_error_count"] != float64(1) {
+		t.Fatalf("summary = %v, want 11 requests with 1 client error", summary)
+	}
+
+	for _, entry := range buf.entries(t) {
+		if entry["msg"] == "Request completed" {
+			t.Fatalf("per-request entry logged: %v", entry)
+		}
+	}
+	// Errors are still logged individually with SummaryLogErrors
+	findEntry(t, buf.entries(t), "Client error")
+}
+
+func TestSummaryCloseLogsFinalSummary(t *testing.T) {
+	logger, buf := newTestLogger()
+	summaries := newSummaryAggregator(logger, time.Hour)
+
+	summaries.record("/orders", http.StatusOK, 10*time.Millisecond)
+	summaries.record("/orders", http.StatusInternalServerError, 30*time.Millisecond)
+	summaries.Close()
+	// Closing twice is safe
+	summaries.Close()
+
+	summary := findEntry(t, buf.entries(t), "Request summary")
+	if summary["count"] != float64(2) || summary["error_rate"] != 0.5 {
+		t.Fatalf("summary = %v, want 2 requests at error rate 0.5", summary)
+	}
+
+	select {
+	case <-summaries.done:
+	default:
+		t.Fatal("summary goroutine still running after Close")
+	}
+}
+
+func TestShutdownStopsSummaries(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(StructuredLogger(StructuredLoggerConfig{Logger: logger, SummaryOnly: true, SummaryInterval: time.Hour}))
+	r.GET("/", ok)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if err := Shutdown(); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	summary := findEntry(t, buf.entries(t), "Request summary")
+	if summary["count"] != float64(1) {
+		t.Fatalf("summary = %v, want the final interval", summary)
+	}
+}
@@ -0,0 +1,127 @@
+package ginlogger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// throttleKey identifies a repeated security warning
+type throttleKey struct {
+	ip     string
+	reason string
+}
+
+type throttleEntry struct {
+	first      time.Time
+	suppressed int
+}
+
+// securityThrottle lets a warning per (IP, reason) through at most once per
+// window and periodically logs how many were suppressed. At most capacity
+// pairs are tracked; warnings beyond that are not throttled.
+type securityThrottle struct {
+	mu       sync.Mutex
+	window   time.Duration
+	capacity int
+	entries  map[throttleKey]*throttleEntry
+	logger   Logger
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// newSecurityThrottle starts logging suppression summaries to logger every
+// window until Close is called
+func newSecurityThrottle(logger Logger, window time.Duration, capacity int) *securityThrottle {
+	t := &securityThrottle{
+		window:   window,
+		capacity: capacity,
+		entries:  make(map[throttleKey]*throttleEntry),
+		logger:   logger,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	go t.run()
+	return t
+}
+
+func (t *securityThrottle) run() {
+	defer close(t.done)
+
+	ticker := time.NewTicker(t.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case now := <-ticker.C:
+			t.flush(now)
+		case <-t.stop:
+			t.flush(time.Now())
+			return
+		}
+	}
+}
+
+// Close stops the periodic summaries and logs the warnings suppressed so far
+func (t *securityThrottle) Close() {
+	t.once.Do(func() {
+		close(t.stop)
+	})
+	<-t.done
+}
+
+// Allow reports whether the warning for ip and reason should be logged at now
+func (t *securityThrottle) Allow(ip, reason string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := throttleKey{ip: ip, reason: reason}
+	entry, ok := t.entries[key]
+	if !ok {
+		if len(t.entries) < t.capacity {
+			t.entries[key] = &throttleEntry{first: now}
+		}
+		return true
+	}
+
+	if now.Sub(entry.first) < t.window {
+		entry.suppressed++
+		return false
+	}
+
+	entry.first = now
+	return true
+}
+
+// flush logs the suppressed counts and forgets pairs whose window ended
+func (t *securityThrottle) flush(now time.Time) {
+	type summary struct {
+		key        throttleKey
+		suppressed int
+	}
+
+	t.mu.Lock()
+	var summaries []summary
+	for key, entry := range t.entries {
+		if entry.suppressed > 0 {
+			summaries = append(summaries, summary{key: key, suppressed: entry.suppressed})
+			entry.suppressed = 0
+		}
+		if now.Sub(entry.first) >= t.window {
+			delete(t.entries, key)
+		}
+	}
+	t.mu.Unlock()
+
+	for _, s := range summaries {
+		t.logger.Warn("Suspicious requests suppressed",
+			zap.String("ip", s.key.ip),
+			zap.String("reason", s.key.reason),
+			zap.Int("suppressed_count", s.suppressed),
+			zap.Duration("window", t.window),
+		)
+	}
+}
@@ -0,0 +1,63 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSecurityLoggerThrottle(t *testing.T) {
+	logger, buf := newTestLogger()
+	r := gin.New()
+	r.Use(SecurityLoggerWithConfig(SecurityLoggerConfig{Logger: logger, ThrottleWindow: time.Hour}))
+	r.GET("/*path", ok)
+
+	for range 5 {
+		req := httptest.NewRequest(http.MethodGet, "/files/../../etc/passwd", nil)
+		req.RemoteAddr = "203.0.113.7:1234"
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if n := countEntries(t, buf, "Suspicious request detected"); n != 1 {
+		t.Fatalf("logged %d warnings, want 1 per window", n)
+	}
+
+	if err := Shutdown(); err != nil {
+		t.Fatalf("Shutdown() = %v", err)
+	}
+
+	summary := findEntry(t, buf.entries(t), "Suspicious requests suppressed")
+	if summary["suppressed_count"] != float64(4) || summary["reason"] != "Path traversal attempt" || summary["ip"] != "203.0.113.7" {
+		t.Fatalf("summary = %v, want 4 suppressed path traversal attempts", summary)
+	}
+}
+
+func TestSecurityThrottleClose(t *testing.T) {
+	logger, buf := newTestLogger()
+	throttle := newSecurityThrottle(logger, time.Hour, 10)
+
+	now := time.Now()
+	throttle.Allow("203.0.113.7", "XSS attempt", now)
+	if throttle.Allow("203.0.113.7", "XSS attempt", now) {
+		t.Fatal("second warning within the window allowed")
+	}
+	if !throttle.Allow("203.0.113.8", "XSS attempt", now) {
+		t.Fatal("warning for another IP throttled")
+	}
+
+	throttle.Close()
+	// Closing twice is safe
+	throttle.Close()
+
+	select {
+	case <-throttle.done:
+	default:
+		t.Fatal("throttle goroutine still running after Close")
+	}
+	if n := countEntries(t, buf, "Suspicious requests suppressed"); n != 1 {
+		t.Fatalf("logged %d suppression summaries on Close, want 1", n)
+	}
+}
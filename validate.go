@@ -0,0 +1,122 @@
+package ginlogger
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Validate checks the configuration for values StructuredLogger cannot
+// honor, such as negative sizes, sample rates outside 0.0-1.0 or nil
+// patterns. All problems are reported in one joined error.
+func (config StructuredLoggerConfig) Validate() error {
+	var errs []error
+
+	errs = appendNegative(errs, "MaxBodySize", config.MaxBodySize)
+	errs = appendNegative(errs, "MaxAllowedSize", config.MaxAllowedSize)
+	errs = appendNegative(errs, "MaxBodyArrayElements", int64(config.MaxBodyArrayElements))
+	errs = appendNegative(errs, "MaxURLLength", int64(config.MaxURLLength))
+	errs = appendNegative(errs, "MaxLoggedHeaders", int64(config.MaxLoggedHeaders))
+	errs = appendNegative(errs, "InlineBodyThreshold", int64(config.InlineBodyThreshold))
+	errs = appendNegative(errs, "DumpMaxFiles", int64(config.DumpMaxFiles))
+	errs = appendNegative(errs, "RecentLogsCapacity", int64(config.RecentLogsCapacity))
+	errs = appendNegative(errs, "RetryCacheSize", int64(config.RetryCacheSize))
+	for header, length := range config.TruncateHeaders {
+		errs = appendNegative(errs, fmt.Sprintf("TruncateHeaders[%q]", header), int64(length))
+	}
+
+	errs = appendNegativeDuration(errs, "LogWriteTimeout", config.LogWriteTimeout)
+	errs = appendNegativeDuration(errs, "SampleKeyWindow", config.SampleKeyWindow)
+	errs = appendNegativeDuration(errs, "SummaryInterval", config.SummaryInterval)
+	errs = appendNegativeDuration(errs, "DumpMaxAge", config.DumpMaxAge)
+	errs = appendNegativeDuration(errs, "HangWatchdog", config.HangWatchdog)
+	errs = appendNegativeDuration(errs, "RetryWindow", config.RetryWindow)
+	for name, threshold := range config.SlowSpanThresholds {
+		errs = appendNegativeDuration(errs, fmt.Sprintf("SlowSpanThresholds[%q]", name), threshold)
+	}
+
+	errs = appendRate(errs, "BodySampleRate", config.BodySampleRate)
+	errs = appendRate(errs, "DiagnosticSampleRate", config.DiagnosticSampleRate)
+	errs = appendRate(errs, "SampleRate", config.SampleRate)
+	for route, rate := range config.PerRouteSampleRate {
+		errs = appendRate(errs, fmt.Sprintf("PerRouteSampleRate[%q]", route), rate)
+	}
+
+	for i, regex := range config.SkipPathRegexps {
+		if regex == nil {
+			errs = append(errs, fmt.Errorf("SkipPathRegexps[%d] is nil", i))
+		}
+	}
+	for i, pattern := range config.PIIPatterns {
+		if pattern == nil {
+			errs = append(errs, fmt.Errorf("PIIPatterns[%d] is nil", i))
+		}
+	}
+	for i, rule := range config.PathLevelOverrides {
+		if rule.Path == nil {
+			errs = append(errs, fmt.Errorf("PathLevelOverrides[%d].Path is nil", i))
+		}
+		errs = appendLevel(errs, fmt.Sprintf("PathLevelOverrides[%d].Level", i), rule.Level)
+	}
+
+	errs = appendLevel(errs, "RateLimitLevel", config.RateLimitLevel)
+	if config.RateLimitStatus != 0 && (config.RateLimitStatus < 100 || config.RateLimitStatus > 599) {
+		errs = append(errs, fmt.Errorf("RateLimitStatus %d is not a valid HTTP status", config.RateLimitStatus))
+	}
+
+	if config.CaptureFailedRequests && config.FailedRequestDir == "" && config.FailedRequestWriter == nil {
+		errs = append(errs, errors.New("CaptureFailedRequests requires FailedRequestDir or FailedRequestWriter"))
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid StructuredLoggerConfig: %w", err)
+	}
+	return nil
+}
+
+// Validate checks the configuration for values SecurityLogger cannot honor
+func (config SecurityLoggerConfig) Validate() error {
+	var errs []error
+
+	errs = appendNegative(errs, "MaxURLLength", int64(config.MaxURLLength))
+	errs = appendNegative(errs, "AbuseThreshold", int64(config.AbuseThreshold))
+	errs = appendNegative(errs, "AbuseMaxTrackedIPs", int64(config.AbuseMaxTrackedIPs))
+	errs = appendNegativeDuration(errs, "AbuseWindow", config.AbuseWindow)
+	errs = appendNegativeDuration(errs, "ThrottleWindow", config.ThrottleWindow)
+	errs = appendNegative(errs, "ThrottleMaxTracked", int64(config.ThrottleMaxTracked))
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid SecurityLoggerConfig: %w", err)
+	}
+	return nil
+}
+
+func appendNegative(errs []error, name string, value int64) []error {
+	if value < 0 {
+		return append(errs, fmt.Errorf("%s must not be negative, got %d", name, value))
+	}
+	return errs
+}
+
+func appendNegativeDuration(errs []error, name string, value time.Duration) []error {
+	if value < 0 {
+		return append(errs, fmt.Errorf("%s must not be negative, got %s", name, value))
+	}
+	return errs
+}
+
+func appendRate(errs []error, name string, value float64) []error {
+	if value != NeverSample && (value < 0 || value > 1) {
+		return append(errs, fmt.Errorf("%s must be between 0 and 1 or NeverSample, got %g", name, value))
+	}
+	return errs
+}
+
+// appendLevel reports levels logAtLevel does not know; empty means the default
+func appendLevel(errs []error, name string, level string) []error {
+	switch level {
+	case "", LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal, LevelPanic:
+		return errs
+	}
+	return append(errs, fmt.Errorf("%s %q is not a known level", name, level))
+}
@@ -0,0 +1,72 @@
+package ginlogger
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStructuredLoggerConfigValidate(t *testing.T) {
+	config := StructuredLoggerConfig{
+		MaxBodySize:           -1,
+		TruncateHeaders:       map[string]int{"Authorization": -5},
+		HangWatchdog:          -time.Second,
+		PerRouteSampleRate:    map[string]float64{"/health": 2},
+		SkipPathRegexps:       []*regexp.Regexp{nil},
+		PathLevelOverrides:    []PathLevelRule{{Path: regexp.MustCompile("^/admin"), Level: "loud"}},
+		RateLimitStatus:       1000,
+		CaptureFailedRequests: true,
+	}
+
+	err := config.Validate()
+	if err == nil {
+		t.Fatal("Validate() accepted an invalid config")
+	}
+	for _, want := range []string{
+		"invalid StructuredLoggerConfig",
+		"MaxBodySize must not be negative",
+		`TruncateHeaders["Authorization"]`,
+		"HangWatchdog must not be negative",
+		`PerRouteSampleRate["/health"] must be between 0 and 1`,
+		"SkipPathRegexps[0] is nil",
+		`PathLevelOverrides[0].Level "loud" is not a known level`,
+		"RateLimitStatus 1000",
+		"CaptureFailedRequests requires",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error does not report %q:\n%v", want, err)
+		}
+	}
+
+	if err := (StructuredLoggerConfig{}).Validate(); err != nil {
+		t.Errorf("Validate() of the zero config = %v", err)
+	}
+}
+
+func TestStructuredLoggerE(t *testing.T) {
+	if handler, err := StructuredLoggerE(StructuredLoggerConfig{MaxURLLength: -1}); err == nil || handler != nil {
+		t.Fatalf("StructuredLoggerE() = %v, %v, want an error and no handler", handler != nil, err)
+	}
+	if handler, err := StructuredLoggerE(StructuredLoggerConfig{}); err != nil || handler == nil {
+		t.Fatalf("StructuredLoggerE() = %v, %v, want a handler", handler != nil, err)
+	}
+}
+
+func TestInvalidConfigPanics(t *testing.T) {
+	tests := map[string]func(){
+		"StructuredLogger": func() { StructuredLogger(StructuredLoggerConfig{MaxBodySize: -1}) },
+		"SecurityLogger":   func() { SecurityLoggerWithConfig(SecurityLoggerConfig{AbuseWindow: -time.Minute}) },
+	}
+
+	for name, construct := range tests {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recovered := recover(); recovered == nil || !strings.HasPrefix(recovered.(string), "ginlogger: invalid "+name+"Config") {
+					t.Errorf("recovered %v, want a panic naming the invalid config", recovered)
+				}
+			}()
+			construct()
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package ginlogger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// maxStackDumpSize bounds the buffer used to dump all goroutine stacks
+const maxStackDumpSize = 8 << 20
+
+// startHangWatchdog logs an in_flight_slow warning with the stack of the
+// goroutine serving the request if it is still running after threshold. The
+// returned function stops the watchdog and must be called when the request
+// completes. No goroutine runs unless the threshold is reached.
+func startHangWatchdog(c *gin.Context, logger Logger, threshold time.Duration, path string) (stop func()) {
+	start := time.Now()
+	id := goroutineID()
+
+	// Copy what the warning needs, the context may be reused once the request completes
+	fields := []zap.Field{
+		zap.Bool("in_flight_slow", true),
+		zap.String("method", c.Request.Method),
+		zap.String("path", path),
+	}
+	if requestID := c.GetString("request_id"); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+
+	timer := time.AfterFunc(threshold, func() {
+		fields = append(fields, zap.Duration("elapsed", time.Since(start)))
+		if stack := goroutineStack(id); stack != "" {
+			fields = append(fields, zap.String("stack", stack))
+		}
+		logger.Warn("Request still in flight", fields...)
+	})
+
+	return func() { timer.Stop() }
+}
+
+// goroutineStack returns the stack trace of the goroutine with the given ID
+func goroutineStack(id uint64) string {
+	if id == 0 {
+		return ""
+	}
+
+	var stacks []byte
+	for size := 64 << 10; ; size *= 2 {
+		buf := make([]byte, size)
+		n := runtime.Stack(buf, true)
+		if n < size || size >= maxStackDumpSize {
+			stacks = buf[:n]
+			break
+		}
+	}
+
+	// Stacks are separated by blank lines and start with "goroutine N ["
+	header := []byte("goroutine " + strconv.FormatUint(id, 10) + " [")
+	for stack := range bytes.SplitSeq(stacks, []byte("\n\n")) {
+		if bytes.HasPrefix(stack, header) {
+			return string(stack)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package ginlogger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// hungHandler blocks until release is closed
+func hungHandler(release chan struct{}) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		<-release
+		c.Status(http.StatusOK)
+	}
+}
+
+func TestHangWatchdog(t *testing.T) {
+	logger, buf := newTestLogger()
+	middleware := StructuredLogger(StructuredLoggerConfig{Logger: logger, HangWatchdog: 20 * time.Millisecond})
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serve(httptest.NewRequest(http.MethodGet, "/slow", nil), "/slow", hungHandler(release), middleware)
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "Request still in flight") {
+		if time.Now().After(deadline) {
+			close(release)
+			t.Fatal("no warning for the hung request")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	close(release)
+	<-done
+
+	entries := buf.entries(t)
+	warning := findEntry(t, entries, "Request still in flight")
+	if warning["in_flight_slow"] != true || warning["path"] != "/slow" || warning["level"] != "warn" {
+		t.Errorf("warning = %v, want in_flight_slow for /slow", warning)
+	}
+	if stack, _ := warning["stack"].(string); !strings.Contains(stack, "hungHandler") {
+		t.Errorf("stack does not show the blocked handler:\n%s", stack)
+	}
+	findEntry(t, entries, "Request completed")
+}
+
+func TestHangWatchdogFastRequest(t *testing.T) {
+	logger, buf := newTestLogger()
+	serve(httptest.NewRequest(http.MethodGet, "/", nil), "/", ok, StructuredLogger(StructuredLoggerConfig{Logger: logger, HangWatchdog: 20 * time.Millisecond}))
+
+	time.Sleep(50 * time.Millisecond)
+	if strings.Contains(buf.String(), "Request still in flight") {
+		t.Errorf("warning logged for a completed request:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,102 @@
+package ginlogger
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timingWriter records when the first byte of the response was sent and,
+// with responseTimeHeader set, adds the elapsed time since start as the
+// X-Response-Time header just before the headers are written
+type timingWriter struct {
+	gin.ResponseWriter
+	start              time.Time
+	firstByte          time.Time
+	responseTimeHeader bool
+}
+
+func (w *timingWriter) markFirstByte() {
+	if w.firstByte.IsZero() {
+		w.firstByte = time.Now()
+		w.setResponseTime()
+	}
+}
+
+// setResponseTime sets X-Response-Time if enabled and the headers are not
+// written yet
+func (w *timingWriter) setResponseTime() {
+	if w.responseTimeHeader && !w.Written() {
+		w.Header().Set("X-Response-Time", time.Since(w.start).Round(time.Microsecond).String())
+	}
+}
+
+func (w *timingWriter) WriteHeaderNow() {
+	w.markFirstByte()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+func (w *timingWriter) Write(data []byte) (int, error) {
+	w.markFirstByte()
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timingWriter) WriteString(s string) (int, error) {
+	w.markFirstByte()
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timingWriter) Flush() {
+	w.markFirstByte()
+	w.ResponseWriter.Flush()
+}
+
+// statusTrackingWriter records whether the handler set a status. gin defers
+// writing it until after the middleware chain, so Written is still false for
+// a handler that only called c.Status.
+type statusTrackingWriter struct {
+	gin.ResponseWriter
+	statusSet bool
+}
+
+func (w *statusTrackingWriter) WriteHeader(code int) {
+	w.statusSet = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// bodyCaptureWriter copies up to limit bytes of the response body. With
+// onlyServerErrors set, bytes are only buffered once a 5xx status has been
+// written, so successful responses never allocate a buffer.
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	body             bytes.Buffer
+	limit            int64
+	onlyServerErrors bool
+	truncated        bool
+}
+
+func (w *bodyCaptureWriter) capture(data []byte) {
+	if w.onlyServerErrors && w.Status() < 500 {
+		return
+	}
+
+	remaining := w.limit - int64(w.body.Len())
+	if int64(len(data)) > remaining {
+		data = data[:max(remaining, 0)]
+		w.truncated = true
+	}
+	w.body.Write(data)
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(data)
+	w.capture(data[:n])
+	return n, err
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.capture([]byte(s[:n]))
+	return n, err
+}